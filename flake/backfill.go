@@ -0,0 +1,97 @@
+package flake
+
+import (
+	"sync"
+	"time"
+)
+
+// Backfiller generates ids for historical times, ie. so that ids minted for
+// migrated data have timestamps that match its created_at values. The sequence
+// #'s of each interval are tracked independently of any live Generator, so
+// the IDGenerator of a Backfiller must have a node value (ie. process ID)
+// that is reserved for backfilling
+type Backfiller interface {
+	IDGenerator
+
+	// GenerateAt generates count ids for the interval that includes t. Each
+	// call continues from the last sequence # allocated for the interval, and
+	// if the interval has fewer than count sequence #'s remaining none are
+	// allocated and ErrIntervalExhausted is returned. ErrTimeOutOfRange is
+	// returned if t cannot be represented by the Layout
+	GenerateAt(t time.Time, count int) ([]byte, error)
+
+	// Decode provides access to the fields of an id created by the Backfiller
+	Decode(id []byte) (DecodedID, error)
+}
+
+// backfiller is an implementation of Backfiller
+//
+//	sequences is the next sequence # of each interval (units since the Unix
+//		Epoch) that ids have been generated for. An entry is kept for every
+//		interval, as backfilled times are rarely in order
+type backfiller struct {
+	IDGenerator
+	unit      time.Duration
+	sequences map[int64]uint64
+	mutex     sync.Mutex
+}
+
+// NewBackfiller creates an instance of backfiller (which implements Backfiller)
+// that uses idGen to synthesize ids. idGen must have a node value that is not
+// used by any live Generator
+func NewBackfiller(idGen IDGenerator) Backfiller {
+	return &backfiller{
+		IDGenerator: idGen,
+		unit:        idGen.Layout().Unit(),
+		sequences:   make(map[int64]uint64),
+	}
+}
+
+// NewOvertFlakeBackfiller creates a Backfiller for overt-flake ids that uses
+// the process ID slot for hardwareID. The ids never collide with those of a
+// live overt-flake Generator provided slot is reserved for backfilling: no
+// live Generator with hardwareID may have a process ID whose 16
+// least-significant bits are slot (eg. on a host whose pid_max is below 65536,
+// any value of at least pid_max). The sequence #'s are only tracked in memory,
+// so only 1 Backfiller may use a slot at a time, and a restarted backfill must
+// use a different slot for intervals it has already generated ids for
+func NewOvertFlakeBackfiller(epoch int64, hardwareID HardwareID, slot uint16) Backfiller {
+	return NewBackfiller(NewOvertFlakeIDSynthesizer(epoch, DefaultSequenceBits, hardwareID, int(slot)))
+}
+
+// GenerateAt implements Backfiller.GenerateAt
+func (bf *backfiller) GenerateAt(t time.Time, count int) ([]byte, error) {
+	if _, err := bf.Layout().TimestampForTime(t); err != nil {
+		return nil, err
+	}
+
+	interval := t.UnixNano() / int64(bf.unit)
+
+	bf.mutex.Lock()
+	sequence := bf.sequences[interval]
+	if uint64(count) > bf.MaxSequenceNumber()-sequence+1 {
+		bf.mutex.Unlock()
+		return nil, ErrIntervalExhausted
+	}
+	bf.sequences[interval] = sequence + uint64(count)
+	bf.mutex.Unlock()
+
+	results := make([]byte, bf.IDSize()*count)
+
+	var index int
+	for j := 0; j < count; j++ {
+		index += bf.SynthesizeID(results, index, interval, sequence+uint64(j))
+	}
+
+	return results, nil
+}
+
+// Decode implements Backfiller.Decode using the Layout of the IDGenerator,
+// or the IDGenerator itself if it implements IDDecoder
+func (bf *backfiller) Decode(id []byte) (DecodedID, error) {
+	if decoder, ok := bf.IDGenerator.(IDDecoder); ok {
+		return decoder.Decode(id)
+	}
+
+	return bf.Layout().Decode(id)
+}
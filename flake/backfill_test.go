@@ -0,0 +1,110 @@
+package flake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackfillSlot is the process ID slot reserved for backfilling, as no
+// live generator of the tests uses it
+const testBackfillSlot = 0xFFFF
+
+func TestOvertFlakeBackfiller(t *testing.T) {
+	backfiller := NewOvertFlakeBackfiller(OvertoneEpochMs, testHardwareID, testBackfillSlot)
+	createdAt := time.Date(2018, 3, 14, 15, 9, 26, 535897932, time.UTC)
+
+	ids, err := backfiller.GenerateAt(createdAt, 2)
+	assert.NoError(t, err)
+	assertIncreasing(t, backfiller.IDSize(), ids)
+
+	more, err := backfiller.GenerateAt(createdAt.Add(time.Microsecond), 2)
+	assert.NoError(t, err)
+	assertIncreasing(t, backfiller.IDSize(), append(ids, more...))
+
+	all := append(ids, more...)
+	for index := 0; index < 4; index++ {
+		decoded, err := backfiller.Decode(all[index*OvertFlakeIDLength : (index+1)*OvertFlakeIDLength])
+		assert.NoError(t, err)
+		assert.True(t, createdAt.Truncate(time.Millisecond).Equal(decoded.Time()))
+		assert.Equal(t, uint64(index), decoded.Sequence())
+
+		ofid := decoded.(OvertFlakeID)
+		assert.Equal(t, uint16(testBackfillSlot), ofid.ProcessID())
+	}
+}
+
+func TestBackfillersWithDifferentSlots(t *testing.T) {
+	first := NewOvertFlakeBackfiller(OvertoneEpochMs, testHardwareID, testBackfillSlot)
+	second := NewOvertFlakeBackfiller(OvertoneEpochMs, testHardwareID, testBackfillSlot-1)
+
+	ids, err := first.GenerateAt(testStartTime, 3)
+	assert.NoError(t, err)
+	more, err := second.GenerateAt(testStartTime, 3)
+	assert.NoError(t, err)
+
+	for index := 0; index < len(ids); index += OvertFlakeIDLength {
+		assert.False(t, bytes.Equal(ids[index:index+OvertFlakeIDLength], more[index:index+OvertFlakeIDLength]))
+	}
+}
+
+func TestBackfillerNeverCollidesWithLiveIDs(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	live := NewOvertFlakeGenerator(OvertoneEpochMs, testHardwareID, 42, 0, WithClock(clock))
+	backfiller := NewOvertFlakeBackfiller(OvertoneEpochMs, testHardwareID, testBackfillSlot)
+
+	liveIDs, err := live.Generate(3)
+	assert.NoError(t, err)
+	backfilled, err := backfiller.GenerateAt(testStartTime, 3)
+	assert.NoError(t, err)
+
+	// the same interval and sequence #'s, but a different node
+	for index := 0; index < len(liveIDs); index += OvertFlakeIDLength {
+		assert.Equal(t, liveIDs[index:index+8], backfilled[index:index+8])
+		assert.False(t, bytes.Equal(liveIDs[index:index+OvertFlakeIDLength], backfilled[index:index+OvertFlakeIDLength]))
+	}
+
+	// backfilling does not affect the live generator
+	more, err := live.Generate(1)
+	assert.NoError(t, err)
+	decoded, err := live.Decode(more)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), decoded.Sequence())
+}
+
+func TestBackfillerExhaustion(t *testing.T) {
+	idGen, err := NewSnowflakeSynthesizer(InstagramSnowflake, 8191, 0)
+	assert.NoError(t, err)
+	backfiller := NewBackfiller(idGen)
+	createdAt := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err = backfiller.GenerateAt(createdAt, 1000)
+	assert.NoError(t, err)
+
+	// 24 sequence #'s remain, and a partial allocation is not made
+	_, err = backfiller.GenerateAt(createdAt, 25)
+	assert.Equal(t, ErrIntervalExhausted, err)
+
+	ids, err := backfiller.GenerateAt(createdAt, 24)
+	assert.NoError(t, err)
+	decoded, err := backfiller.Decode(ids[len(ids)-8:])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1023), decoded.Sequence())
+
+	_, err = backfiller.GenerateAt(createdAt, 1)
+	assert.Equal(t, ErrIntervalExhausted, err)
+
+	// other intervals are unaffected
+	_, err = backfiller.GenerateAt(createdAt.Add(-time.Millisecond), 1024)
+	assert.NoError(t, err)
+}
+
+func TestBackfillerTimeOutOfRange(t *testing.T) {
+	backfiller := NewOvertFlakeBackfiller(OvertoneEpochMs, testHardwareID, testBackfillSlot)
+
+	_, err := backfiller.GenerateAt(time.Unix(0, OvertoneEpochMs*int64(time.Millisecond)).Add(-time.Millisecond), 1)
+	assert.True(t, errors.Is(err, ErrTimeOutOfRange))
+}
@@ -0,0 +1,187 @@
+package flake
+
+import "encoding/hex"
+
+// baseEncoding is a fixed-width, big-endian positional encoding of an
+// identifier using an arbitrary alphabet (base62, base58, Crockford base32).
+//
+// Encoded values are always padded to width characters using the zero digit
+// of the alphabet. Provided the alphabet is in ascending ASCII order, as all of
+// the alphabets below are, the encoded strings sort exactly the same way as
+// the bytes of the identifiers they represent
+type baseEncoding struct {
+	alphabet  string
+	base      uint32
+	width     int
+	decodeMap [256]byte
+}
+
+const invalidDigit = 0xFF
+
+const (
+	base62Alphabet          = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	base58Alphabet          = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordBase32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var (
+	// 62^22 > 2^128
+	base62Encoding = newBaseEncoding(base62Alphabet, 22, false)
+	// 58^22 > 2^128
+	base58Encoding = newBaseEncoding(base58Alphabet, 22, false)
+	// 32^26 == 2^130, so the 1st character of a 128-bit value is always 0-7
+	crockfordBase32Encoding = newCrockfordBase32Encoding(26)
+)
+
+// newBaseEncoding creates a baseEncoding for alphabet that encodes to
+// width characters, optionally accepting lower case input for upper case
+// digits
+func newBaseEncoding(alphabet string, width int, caseInsensitive bool) *baseEncoding {
+	enc := &baseEncoding{
+		alphabet: alphabet,
+		base:     uint32(len(alphabet)),
+		width:    width,
+	}
+
+	for i := range enc.decodeMap {
+		enc.decodeMap[i] = invalidDigit
+	}
+
+	for i := 0; i < len(alphabet); i++ {
+		enc.decodeMap[alphabet[i]] = byte(i)
+
+		if caseInsensitive && alphabet[i] >= 'A' && alphabet[i] <= 'Z' {
+			enc.decodeMap[alphabet[i]+('a'-'A')] = byte(i)
+		}
+	}
+
+	return enc
+}
+
+// newCrockfordBase32Encoding creates a case insensitive Crockford base32
+// encoding that also accepts the commonly confused letters I, L and O
+func newCrockfordBase32Encoding(width int) *baseEncoding {
+	enc := newBaseEncoding(crockfordBase32Alphabet, width, true)
+
+	for _, c := range "iIlL" {
+		enc.decodeMap[c] = 1
+	}
+
+	for _, c := range "oO" {
+		enc.decodeMap[c] = 0
+	}
+
+	return enc
+}
+
+// encode returns the fixed-width representation of the big-endian value in src
+func (enc *baseEncoding) encode(src []byte) string {
+	// scratch copy of the value that is repeatedly divided by the base
+	var scratch [32]byte
+	var value []byte
+	if len(src) <= len(scratch) {
+		value = scratch[:len(src)]
+	} else {
+		value = make([]byte, len(src))
+	}
+	copy(value, src)
+
+	out := make([]byte, enc.width)
+
+	// produce the digits from least to most significant
+	for i := enc.width - 1; i >= 0; i-- {
+		var remainder uint32
+		for j := range value {
+			acc := remainder<<8 | uint32(value[j])
+			value[j] = byte(acc / enc.base)
+			remainder = acc % enc.base
+		}
+		out[i] = enc.alphabet[remainder]
+	}
+
+	return string(out)
+}
+
+// decode parses s into dst, which is treated as a big-endian value. dst is
+// overwritten even when an error is returned
+func (enc *baseEncoding) decode(s string, dst []byte) error {
+	if len(s) != enc.width {
+		return ErrInvalidIDSyntax
+	}
+
+	for i := range dst {
+		dst[i] = 0
+	}
+
+	for i := 0; i < len(s); i++ {
+		digit := enc.decodeMap[s[i]]
+		if digit == invalidDigit {
+			return ErrInvalidIDSyntax
+		}
+
+		// dst = dst*base + digit
+		carry := uint32(digit)
+		for j := len(dst) - 1; j >= 0; j-- {
+			acc := uint32(dst[j])*enc.base + carry
+			dst[j] = byte(acc)
+			carry = acc >> 8
+		}
+
+		if carry != 0 {
+			return ErrIDOutOfRange
+		}
+	}
+
+	return nil
+}
+
+// uuidLength is the length of the 8-4-4-4-12 representation of a 16-byte value
+const uuidLength = 36
+
+// encodeHex returns the lower case, zero-padded hexadecimal representation of
+// src (2 characters per byte)
+func encodeHex(src []byte) string {
+	return hex.EncodeToString(src)
+}
+
+// decodeHex parses exactly 2*len(dst) upper or lower case hexadecimal digits
+// into dst
+func decodeHex(s string, dst []byte) error {
+	if len(s) != hex.EncodedLen(len(dst)) {
+		return ErrInvalidIDSyntax
+	}
+
+	if _, err := hex.Decode(dst, []byte(s)); err != nil {
+		return ErrInvalidIDSyntax
+	}
+
+	return nil
+}
+
+// encodeUUID returns the lower case 8-4-4-4-12 representation of a 16-byte
+// value
+func encodeUUID(src []byte) string {
+	out := make([]byte, uuidLength)
+
+	hex.Encode(out[0:8], src[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], src[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], src[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], src[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], src[10:16])
+
+	return string(out)
+}
+
+// decodeUUID parses the 8-4-4-4-12 representation of a 16-byte value into dst.
+// Upper and lower case hexadecimal digits are accepted
+func decodeUUID(s string, dst []byte) error {
+	if len(s) != uuidLength || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return ErrInvalidIDSyntax
+	}
+
+	return decodeHex(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:], dst)
+}
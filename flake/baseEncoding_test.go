@@ -0,0 +1,137 @@
+package flake
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIDs(t *testing.T, count int) []OvertFlakeID {
+	gen := NewOvertoneEpochGenerator(testHardwareID)
+
+	ids := []OvertFlakeID{
+		NewOvertFlakeID(make([]byte, OvertFlakeIDLength)),
+		NewOvertFlakeID(bytes.Repeat([]byte{0xFF}, OvertFlakeIDLength)),
+	}
+
+	for i := 0; i < count; i++ {
+		idBytes, err := gen.Generate(1)
+		assert.NoError(t, err)
+
+		// vary the lower bits so that we are not only testing the timestamp
+		idBytes[15] = byte(i * 37)
+		ids = append(ids, NewOvertFlakeID(idBytes))
+	}
+
+	return ids
+}
+
+func TestEncodingRoundTrips(t *testing.T) {
+	codecs := []struct {
+		name   string
+		encode func(OvertFlakeID) string
+		parse  func(string) (OvertFlakeID, error)
+		width  int
+	}{
+		{"base62", OvertFlakeID.Base62, ParseOvertFlakeIDBase62, 22},
+		{"base58", OvertFlakeID.Base58, ParseOvertFlakeIDBase58, 22},
+		{"base32", OvertFlakeID.Base32, ParseOvertFlakeIDBase32, 26},
+		{"hex", OvertFlakeID.Hex, ParseOvertFlakeIDHex, 32},
+		{"uuid", OvertFlakeID.UUID, ParseOvertFlakeIDUUID, 36},
+	}
+
+	ids := testIDs(t, 64)
+
+	for _, codec := range codecs {
+		encoded := make([]string, len(ids))
+
+		for i, id := range ids {
+			encoded[i] = codec.encode(id)
+			assert.Equal(t, codec.width, len(encoded[i]), "%s: unexpected width for %q", codec.name, encoded[i])
+
+			parsed, err := codec.parse(encoded[i])
+			assert.NoError(t, err, codec.name)
+			if err == nil {
+				assert.Equal(t, id.Bytes(), parsed.Bytes(), codec.name)
+			}
+		}
+
+		// the text forms must sort the same way as the bytes
+		sortedIDs := append([]OvertFlakeID(nil), ids...)
+		sort.Slice(sortedIDs, func(i, j int) bool {
+			return bytes.Compare(sortedIDs[i].Bytes(), sortedIDs[j].Bytes()) < 0
+		})
+		sort.Strings(encoded)
+
+		for i := range sortedIDs {
+			assert.Equal(t, codec.encode(sortedIDs[i]), encoded[i], "%s: sort order differs at %d", codec.name, i)
+		}
+	}
+}
+
+func TestEncodingKnownValues(t *testing.T) {
+	zero := NewOvertFlakeID(make([]byte, OvertFlakeIDLength))
+	assert.Equal(t, strings.Repeat("0", 22), zero.Base62())
+	assert.Equal(t, strings.Repeat("1", 22), zero.Base58())
+	assert.Equal(t, strings.Repeat("0", 26), zero.Base32())
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", zero.UUID())
+
+	max := NewOvertFlakeID(bytes.Repeat([]byte{0xFF}, OvertFlakeIDLength))
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", max.Base32())
+	assert.Equal(t, "7n42DGM5Tflk9n8mt7Fhc7", max.Base62())
+	assert.Equal(t, "YcVfxkQb6JRzqk5kF2tNLv", max.Base58())
+	assert.Equal(t, "ffffffff-ffff-ffff-ffff-ffffffffffff", max.UUID())
+}
+
+func TestBase32IsCaseInsensitive(t *testing.T) {
+	id := MustParseOvertFlakeID("0x0123456789abcdef0123456789abcdef")
+	encoded := id.Base32()
+
+	parsed, err := ParseOvertFlakeIDBase32(strings.ToLower(encoded))
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+
+	// I, L and O are aliases for 1, 1 and 0
+	one, err := ParseOvertFlakeIDBase32(strings.Repeat("0", 25) + "1")
+	assert.NoError(t, err)
+	for _, alias := range []string{"i", "I", "l", "L"} {
+		parsed, err = ParseOvertFlakeIDBase32(strings.Repeat("O", 25) + alias)
+		assert.NoError(t, err)
+		assert.Equal(t, one.Bytes(), parsed.Bytes())
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	_, err := ParseOvertFlakeIDBase62("0")
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+
+	_, err = ParseOvertFlakeIDBase62(strings.Repeat("0", 21) + "-")
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+
+	_, err = ParseOvertFlakeIDBase62(strings.Repeat("z", 22))
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+
+	_, err = ParseOvertFlakeIDBase58(strings.Repeat("z", 22))
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+
+	// 0 is not part of the base58 alphabet
+	_, err = ParseOvertFlakeIDBase58(strings.Repeat("0", 22))
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+
+	_, err = ParseOvertFlakeIDBase32("8" + strings.Repeat("0", 25))
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+
+	// U is excluded from Crockford base32
+	_, err = ParseOvertFlakeIDBase32(strings.Repeat("U", 26))
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+
+	_, err = ParseOvertFlakeIDUUID("00000000000000000000000000000000")
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+
+	_, err = ParseOvertFlakeIDUUID("0000000g-0000-0000-0000-000000000000")
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax))
+}
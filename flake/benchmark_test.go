@@ -0,0 +1,82 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package flake
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+// cpuTime returns the user + system CPU time consumed by the process
+func cpuTime(b *testing.B) time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		b.Fatal(err)
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// benchmarkParallel runs generate in parallel and reports the CPU time
+// consumed per id (cpu-ns/id) alongside the latency (ns/op)
+func benchmarkParallel(b *testing.B, generate func() error) {
+	b.ReportAllocs()
+
+	start := cpuTime(b)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := generate(); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+
+	b.StopTimer()
+	b.ReportMetric(float64(cpuTime(b)-start)/float64(b.N), "cpu-ns/id")
+}
+
+// BenchmarkWaitStrategy compares the wait strategies when every caller is
+// contending for a small (16 ids per ms) sequence space, so most requests
+// wait for the next interval
+func BenchmarkWaitStrategy(b *testing.B) {
+	for _, strategy := range []WaitStrategy{WaitSpin, WaitYield, WaitSleep, WaitHybrid} {
+		b.Run(strategy.String(), func(b *testing.B) {
+			gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 4, WithWaitStrategy(strategy))
+			buffer := make([]byte, OvertFlakeIDLength)
+
+			benchmarkParallel(b, func() error {
+				_, err := gen.GenerateAsStream(1, buffer, func(int, []byte) error { return nil })
+				return err
+			})
+		})
+	}
+}
+
+// BenchmarkGeneratorParallel compares the mutex and lock-free generators when
+// every caller is contending for the same generator
+func BenchmarkGeneratorParallel(b *testing.B) {
+	constructors := []struct {
+		name string
+		new  func(IDGenerator, int64, ...GeneratorOption) Generator
+	}{
+		{"mutex", NewGenerator},
+		{"lockfree", NewLockFreeGenerator},
+	}
+
+	for _, constructor := range constructors {
+		b.Run(constructor.name, func(b *testing.B) {
+			gen := constructor.new(NewOvertFlakeIDSynthesizer(UnixEpochMs, DefaultSequenceBits, testHardwareID, 42), 0)
+			buffer := make([]byte, OvertFlakeIDLength)
+
+			benchmarkParallel(b, func() error {
+				_, err := gen.GenerateAsStream(1, buffer, func(int, []byte) error { return nil })
+				return err
+			})
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package flake
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCheckpointMargin is the safety margin added to the high-water
+	// mark saved by a Checkpointer, unless otherwise specified
+	DefaultCheckpointMargin = 5 * time.Second
+
+	// DefaultCheckpointInterval is the interval between the checkpoints of a
+	// started Checkpointer, when a non-positive interval is specified
+	DefaultCheckpointInterval = time.Second
+)
+
+// Checkpointer durably saves the high-water mark of a Generator to a state
+// file, so that after a restart (see ReadCheckpoint) ids are never generated
+// for an interval that may have been used before, even if the clock has been
+// set back
+type Checkpointer interface {
+	// Checkpoint saves the high-water mark now
+	Checkpoint() error
+
+	// Start saves the high-water mark every interval (or
+	// DefaultCheckpointInterval if interval <= 0) until Stop is called.
+	// Errors are passed to onError (which may be nil)
+	Start(interval time.Duration, onError func(error))
+
+	// Stop stops periodic checkpoints, and saves a final checkpoint of the
+	// end of the last allocated interval, without the margin, so that a clean
+	// restart does not wait out the margin. The Generator must not generate
+	// ids after Stop
+	Stop() error
+}
+
+// checkpointer is an implementation of Checkpointer
+//
+//	gen is the Generator whose high-water mark is saved
+//	path is the state file
+//	margin is added to the high-water mark to cover ids allocated after the
+//		checkpoint is saved
+//	saved is the last value saved, as the saved value never decreases (until
+//		the final checkpoint of Stop)
+type checkpointer struct {
+	gen    Generator
+	path   string
+	margin time.Duration
+	saved  int64
+	stop   chan struct{}
+	done   chan struct{}
+	mutex  sync.Mutex
+}
+
+// NewCheckpointer creates an instance of checkpointer (which implements
+// Checkpointer) that saves the high-water mark of gen to the state file at
+// path. The high-water mark is the later of LastAllocatedTime() and the
+// current time (so that ids allocated after an idle period are covered) plus
+// margin. margin must exceed the interval between checkpoints
+func NewCheckpointer(gen Generator, path string, margin time.Duration) Checkpointer {
+	return &checkpointer{
+		gen:    gen,
+		path:   path,
+		margin: margin,
+	}
+}
+
+// Checkpoint implements Checkpointer.Checkpoint
+func (cp *checkpointer) Checkpoint() error {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	highWater := time.Now().UnixNano() / int64(time.Millisecond)
+	if lastTime := cp.gen.LastAllocatedTime(); lastTime > highWater {
+		highWater = lastTime
+	}
+
+	highWater += int64(cp.margin / time.Millisecond)
+	if highWater < cp.saved {
+		highWater = cp.saved
+	}
+
+	return cp.save(highWater)
+}
+
+// save writes highWater to the state file. The caller must hold mutex
+func (cp *checkpointer) save(highWater int64) error {
+	if err := WriteCheckpoint(cp.path, highWater); err != nil {
+		return err
+	}
+
+	cp.saved = highWater
+
+	return nil
+}
+
+// Start implements Checkpointer.Start
+func (cp *checkpointer) Start(interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	cp.stop = make(chan struct{})
+	cp.done = make(chan struct{})
+
+	go func() {
+		defer close(cp.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := cp.Checkpoint(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-cp.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop implements Checkpointer.Stop
+func (cp *checkpointer) Stop() error {
+	if cp.stop != nil {
+		close(cp.stop)
+		<-cp.done
+		cp.stop = nil
+	}
+
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	// no more ids are allocated, so the next interval after the last one
+	// allocated is safe to use (waitForTime is rounded up to an interval)
+	return cp.save(cp.gen.LastAllocatedTime() + 1)
+}
+
+// ReadCheckpoint reads the high-water mark (milliseconds since the Unix Epoch)
+// from the state file at path, for use as waitForTime. If the file does not
+// exist then 0 is returned
+func ReadCheckpoint(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	highWater, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, path)
+	}
+
+	return highWater, nil
+}
+
+// WriteCheckpoint durably writes the high-water mark (milliseconds since the
+// Unix Epoch) to the state file at path. The value is written and synced to a
+// temporary file which then atomically replaces the state file, so the state
+// file always holds either the previous value or the new one
+func WriteCheckpoint(path string, highWater int64) error {
+	return writeFileAtomic(path, []byte(strconv.FormatInt(highWater, 10)+"\n"))
+}
+
+// writeFileAtomic durably replaces the file at path with data, by writing and
+// syncing a temporary file that is then renamed to path
+func writeFileAtomic(path string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	tempPath := file.Name()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	// sync the directory so the rename is durable (not supported on all
+	// platforms, so errors are ignored)
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
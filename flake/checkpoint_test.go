@@ -0,0 +1,104 @@
+package flake
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWriteCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ofsrvr.state")
+
+	// a missing state file is not an error
+	highWater, err := ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), highWater)
+
+	assert.NoError(t, WriteCheckpoint(path, 1234567890123))
+	highWater, err = ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234567890123), highWater)
+
+	// no temporary files are left behind
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("garbage"), 0644))
+	_, err = ReadCheckpoint(path)
+	assert.True(t, errors.Is(err, ErrInvalidCheckpoint))
+}
+
+func TestCheckpointer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ofsrvr.state")
+
+	// a generator whose last allocated time is in the future (the interval
+	// before its waitForTime)
+	future := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, future)
+
+	cp := NewCheckpointer(gen, path, 5*time.Second)
+	assert.NoError(t, cp.Checkpoint())
+
+	highWater, err := ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, future-1+5000, highWater)
+
+	// otherwise the current time is used
+	gen = NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+	_, err = gen.Generate(1)
+	assert.NoError(t, err)
+
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	cp = NewCheckpointer(gen, path, time.Second)
+	cp.Start(time.Millisecond, func(err error) {
+		assert.NoError(t, err)
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	highWater, err = ReadCheckpoint(path)
+	assert.NoError(t, err)
+	after := time.Now().UnixNano() / int64(time.Millisecond)
+	assert.True(t, highWater >= before+1000 && highWater <= after+1000)
+	assert.True(t, highWater >= gen.LastAllocatedTime()+1000)
+
+	// the final checkpoint is the end of the last allocated interval, without
+	// the margin
+	assert.NoError(t, cp.Stop())
+	highWater, err = ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, gen.LastAllocatedTime()+1, highWater)
+
+	// and no ids are generated for that interval after a restart
+	restarted := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, highWater)
+	assert.True(t, restarted.(*generator).notBefore > gen.(*generator).lastTime)
+}
+
+func TestCheckpointerDefaultInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ofsrvr.state")
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+
+	cp := NewCheckpointer(gen, path, time.Second)
+	assert.NotPanics(t, func() { cp.Start(0, nil) })
+	assert.NoError(t, cp.Stop())
+}
+
+func TestCheckpointNeverDecreases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ofsrvr.state")
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+
+	cp := NewCheckpointer(gen, path, time.Hour)
+	assert.NoError(t, cp.Checkpoint())
+	first, err := ReadCheckpoint(path)
+	assert.NoError(t, err)
+
+	cp.(*checkpointer).margin = 0
+	assert.NoError(t, cp.Checkpoint())
+	second, err := ReadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
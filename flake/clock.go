@@ -0,0 +1,171 @@
+package flake
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by a Generator to determine the current
+// time interval
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Sleep pauses the current goroutine for at least the duration d
+	Sleep(d time.Duration)
+}
+
+// ContextSleeper is optionally implemented by a Clock whose Sleep can be
+// interrupted. SleepContext returns ctx.Err() if ctx is done before d elapses.
+// Clocks that do not implement it are slept in short slices, checking ctx
+// between each
+type ContextSleeper interface {
+	SleepContext(ctx context.Context, d time.Duration) error
+}
+
+// contextSleepSlice is the longest slice of time a Clock that does not
+// implement ContextSleeper is slept for before ctx is checked
+const contextSleepSlice = 10 * time.Millisecond
+
+// sleepContext sleeps on clock for d, or until ctx is done
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	if sleeper, ok := clock.(ContextSleeper); ok {
+		return sleeper.SleepContext(ctx, d)
+	}
+
+	for d > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		slice := d
+		if slice > contextSleepSlice {
+			slice = contextSleepSlice
+		}
+
+		clock.Sleep(slice)
+		d -= slice
+	}
+
+	return ctx.Err()
+}
+
+// sleepTimer sleeps for d (in real time), or until ctx is done
+func sleepTimer(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wallClock implements Clock using the system wall clock
+type wallClock struct{}
+
+// NewWallClock creates an instance of wallClock which implements Clock using
+// time.Now(). It is the default Clock used by generators
+func NewWallClock() Clock {
+	return wallClock{}
+}
+
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+func (wallClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (wallClock) SleepContext(ctx context.Context, d time.Duration) error {
+	return sleepTimer(ctx, d)
+}
+
+// monotonicClock implements Clock by anchoring the monotonic clock to the wall
+// time at which the clock was created
+type monotonicClock struct {
+	start time.Time
+}
+
+// NewMonotonicClock creates an instance of monotonicClock which implements
+// Clock. The time it reports is the wall time when it was created plus the
+// (monotonic) time elapsed since, so it never moves backwards even if the
+// system clock is stepped by NTP or an operator. In exchange, it will drift
+// from the wall clock by however much the system clock is adjusted
+func NewMonotonicClock() Clock {
+	return &monotonicClock{start: time.Now()}
+}
+
+func (clock *monotonicClock) Now() time.Time {
+	// time.Since uses the monotonic reading of start, and Add applies the
+	// elapsed time to its wall reading
+	return clock.start.Add(time.Since(clock.start)).Round(0)
+}
+
+func (clock *monotonicClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (clock *monotonicClock) SleepContext(ctx context.Context, d time.Duration) error {
+	return sleepTimer(ctx, d)
+}
+
+// FakeClock is a manually advanced Clock intended for tests. Sleep advances
+// the clock by the requested duration rather than blocking, so that code which
+// waits on the clock completes deterministically
+type FakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// NewFakeClock creates a FakeClock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+// Sleep advances the clock by d
+func (clock *FakeClock) Sleep(d time.Duration) {
+	clock.Advance(d)
+}
+
+// SleepContext advances the clock by d, unless ctx is already done
+func (clock *FakeClock) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	clock.Advance(d)
+
+	return nil
+}
+
+// Advance moves the clock forward by d (or backwards if d is negative)
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
+}
+
+// Set sets the current time of the clock
+func (clock *FakeClock) Set(now time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = now
+}
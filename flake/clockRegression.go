@@ -0,0 +1,75 @@
+package flake
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClockRegressionPolicy determines how a generator reacts when its Clock
+// reports a time before the last interval for which ids were allocated
+type ClockRegressionPolicy int
+
+const (
+	// ClockRegressionFail fails the request with ErrTimeIsMovingBackwards (the
+	// default)
+	ClockRegressionFail ClockRegressionPolicy = iota
+	// ClockRegressionWait blocks the request until the clock catches up with the
+	// last interval, provided that will happen within the maximum wait
+	ClockRegressionWait
+	// ClockRegressionReuse continues to allocate from the sequence #'s remaining
+	// in the last interval. If they run out before the clock catches up, the
+	// request waits as it would for ClockRegressionWait
+	ClockRegressionReuse
+)
+
+// DefaultClockRegressionMaxWait is the maximum amount of time a request will
+// wait for the clock to catch up, unless otherwise specified
+const DefaultClockRegressionMaxWait = time.Second
+
+// ClockRegression describes a clock regression observed by a generator
+type ClockRegression struct {
+	// Last is the last interval (Units since the Unix Epoch) ids were
+	// allocated for
+	Last int64
+	// Current is the time (Units since the Unix Epoch) reported by the clock
+	Current int64
+	// Unit is the duration of an interval of the generator
+	Unit time.Duration
+	// Policy is the policy applied to the regression
+	Policy ClockRegressionPolicy
+}
+
+// Drift is how far the clock has moved backwards
+func (regression ClockRegression) Drift() time.Duration {
+	return time.Duration(regression.Last-regression.Current) * regression.Unit
+}
+
+// String returns the name of the policy
+func (policy ClockRegressionPolicy) String() string {
+	switch policy {
+	case ClockRegressionFail:
+		return "fail"
+	case ClockRegressionWait:
+		return "wait"
+	case ClockRegressionReuse:
+		return "reuse"
+	}
+
+	return "unknown"
+}
+
+// ParseClockRegressionPolicy converts the name of a policy (fail, wait or
+// reuse) to a ClockRegressionPolicy
+func ParseClockRegressionPolicy(name string) (ClockRegressionPolicy, error) {
+	switch strings.ToLower(name) {
+	case "fail":
+		return ClockRegressionFail, nil
+	case "wait":
+		return ClockRegressionWait, nil
+	case "reuse":
+		return ClockRegressionReuse, nil
+	}
+
+	return ClockRegressionFail, fmt.Errorf("unknown clock regression policy: %s", name)
+}
@@ -0,0 +1,114 @@
+package flake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRegressionTestGenerator creates a generator with 2 sequence bits (4 ids
+// per interval) whose clock has moved backwards by drift after allocating an id
+func newRegressionTestGenerator(t *testing.T, drift time.Duration, opts ...GeneratorOption) (Generator, *FakeClock, *[]ClockRegression) {
+	var regressions []ClockRegression
+
+	clock := NewFakeClock(testStartTime)
+	opts = append([]GeneratorOption{
+		WithClock(clock),
+		WithClockRegressionHandler(func(regression ClockRegression) {
+			regressions = append(regressions, regression)
+		}),
+	}, opts...)
+
+	gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 2, opts...)
+
+	_, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	clock.Advance(-drift)
+
+	return gen, clock, &regressions
+}
+
+func TestClockRegressionFail(t *testing.T) {
+	gen, _, regressions := newRegressionTestGenerator(t, 5*time.Millisecond)
+
+	_, err := gen.Generate(1)
+	assert.Equal(t, ErrTimeIsMovingBackwards, err)
+
+	if assert.Len(t, *regressions, 1) {
+		regression := (*regressions)[0]
+		assert.Equal(t, ClockRegressionFail, regression.Policy)
+		assert.Equal(t, 5*time.Millisecond, regression.Drift())
+		assert.Equal(t, gen.LastAllocatedTime(), regression.Last)
+	}
+}
+
+func TestClockRegressionWait(t *testing.T) {
+	gen, clock, regressions := newRegressionTestGenerator(t, 5*time.Millisecond,
+		WithClockRegressionPolicy(ClockRegressionWait, 10*time.Millisecond))
+	last := gen.LastAllocatedTime()
+
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.Len(t, *regressions, 1)
+
+	// the fake clock was advanced by sleeping until it caught up
+	assert.Equal(t, testStartTime, clock.Now())
+	decoded := decodeAll(t, gen, ids)
+	assert.Equal(t, uint64(last), decoded[0].Timestamp())
+	assert.Equal(t, uint64(1), decoded[0].Sequence())
+}
+
+func TestClockRegressionWaitExceedsMax(t *testing.T) {
+	gen, clock, regressions := newRegressionTestGenerator(t, 50*time.Millisecond,
+		WithClockRegressionPolicy(ClockRegressionWait, 10*time.Millisecond))
+	now := clock.Now()
+
+	_, err := gen.Generate(1)
+	assert.Equal(t, ErrTimeIsMovingBackwards, err)
+	assert.Len(t, *regressions, 1)
+
+	// no time was spent waiting for a clock that would never catch up
+	assert.Equal(t, now, clock.Now())
+}
+
+func TestClockRegressionReuse(t *testing.T) {
+	gen, clock, regressions := newRegressionTestGenerator(t, 5*time.Millisecond,
+		WithClockRegressionPolicy(ClockRegressionReuse, 10*time.Millisecond))
+	last := gen.LastAllocatedTime()
+	now := clock.Now()
+
+	// the remaining 3 sequence #'s of the last interval are used without waiting
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assert.Equal(t, now, clock.Now())
+	for index, id := range decodeAll(t, gen, ids) {
+		assert.Equal(t, uint64(last), id.Timestamp())
+		assert.Equal(t, uint64(index+1), id.Sequence())
+	}
+
+	// the interval is exhausted, so the next request waits for the clock
+	ids, err = gen.Generate(1)
+	assert.NoError(t, err)
+	assert.False(t, clock.Now().Before(testStartTime))
+	decoded := decodeAll(t, gen, ids)
+	assert.True(t, decoded[0].Timestamp() > uint64(last))
+	assert.Equal(t, uint64(0), decoded[0].Sequence())
+
+	assert.Len(t, *regressions, 2)
+	for _, regression := range *regressions {
+		assert.Equal(t, ClockRegressionReuse, regression.Policy)
+	}
+}
+
+func TestParseClockRegressionPolicy(t *testing.T) {
+	for _, policy := range []ClockRegressionPolicy{ClockRegressionFail, ClockRegressionWait, ClockRegressionReuse} {
+		parsed, err := ParseClockRegressionPolicy(policy.String())
+		assert.NoError(t, err)
+		assert.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseClockRegressionPolicy("ignore")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package flake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testStartTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// decodeAll decodes each id in ids using the generator
+func decodeAll(t *testing.T, gen Generator, ids []byte) []DecodedID {
+	var decoded []DecodedID
+
+	for index := 0; index < len(ids); index += gen.IDSize() {
+		id, err := gen.Decode(ids[index : index+gen.IDSize()])
+		assert.NoError(t, err)
+		decoded = append(decoded, id)
+	}
+
+	return decoded
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	assert.Equal(t, testStartTime, clock.Now())
+
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, testStartTime.Add(time.Millisecond), clock.Now())
+
+	clock.Sleep(time.Second)
+	assert.Equal(t, testStartTime.Add(time.Second+time.Millisecond), clock.Now())
+
+	clock.Set(testStartTime)
+	assert.Equal(t, testStartTime, clock.Now())
+}
+
+func TestMonotonicClock(t *testing.T) {
+	before := time.Now()
+	clock := NewMonotonicClock()
+
+	now := clock.Now()
+	assert.False(t, now.Before(before.Round(0)))
+
+	clock.Sleep(time.Millisecond)
+	assert.True(t, clock.Now().After(now))
+
+	// the wall clock is the default
+	assert.False(t, NewWallClock().Now().Before(before))
+}
+
+func TestGeneratorUsesClock(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0, WithClock(clock))
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assert.Equal(t, testStartTime.UnixNano()/int64(time.Millisecond), gen.LastAllocatedTime())
+
+	for i, id := range decodeAll(t, gen, ids) {
+		assert.True(t, testStartTime.Equal(id.Time()))
+		assert.Equal(t, uint64(i), id.Sequence())
+	}
+
+	// a second request in the same interval continues the sequence
+	ids, err = gen.Generate(2)
+	assert.NoError(t, err)
+	for i, id := range decodeAll(t, gen, ids) {
+		assert.True(t, testStartTime.Equal(id.Time()))
+		assert.Equal(t, uint64(i+3), id.Sequence())
+	}
+
+	// interval rollover resets the sequence
+	clock.Advance(time.Millisecond)
+	ids, err = gen.Generate(1)
+	assert.NoError(t, err)
+	id := decodeAll(t, gen, ids)[0]
+	assert.True(t, testStartTime.Add(time.Millisecond).Equal(id.Time()))
+	assert.Equal(t, uint64(0), id.Sequence())
+
+	// a clock regression is detected
+	clock.Advance(-2 * time.Millisecond)
+	_, err = gen.Generate(1)
+	assert.Equal(t, ErrTimeIsMovingBackwards, err)
+}
+
+func TestGeneratorSequenceExhaustion(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	// 2 sequence bits == 4 ids per interval
+	gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 2, WithClock(clock))
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(ids)/OvertFlakeIDLength)
+
+	// the last id of the interval is available, and the remaining 2 must wait
+	// for the clock to move to the next interval
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		clock.Advance(time.Millisecond)
+	}()
+
+	ids, err = gen.Generate(3)
+	assert.NoError(t, err)
+
+	decoded := decodeAll(t, gen, ids)
+	assert.True(t, testStartTime.Equal(decoded[0].Time()))
+	assert.Equal(t, uint64(3), decoded[0].Sequence())
+
+	for i, id := range decoded[1:] {
+		assert.True(t, testStartTime.Add(time.Millisecond).Equal(id.Time()))
+		assert.Equal(t, uint64(i), id.Sequence())
+	}
+}
@@ -15,6 +15,10 @@ const UnixEpochMs = int64(0)
 // (1/1/19070) and the Twitter Snowflake Epoch (2010-11-04 01:42:54 +0000 UTC ??)
 const SnowflakeEpochMs = int64(1288834974657)
 
+// SonyflakeEpochMs is the number of milliseconds elapsed between the Unix Epoch
+// (1/1/1970) and the default Sonyflake start time (2014-09-01 00:00:00 +0000 UTC)
+const SonyflakeEpochMs = int64(1409529600000)
+
 // OvertFlakeIDLength is the length, in bytes, of an Overt-flake ID
 const OvertFlakeIDLength = 16
 
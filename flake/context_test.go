@@ -0,0 +1,129 @@
+package flake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepOnlyClock is a Clock that does not implement ContextSleeper
+type sleepOnlyClock struct {
+	Clock
+}
+
+// stoppedClock is a Clock that never advances, although sleeping on it takes
+// real time
+type stoppedClock struct {
+	now time.Time
+}
+
+func (clock stoppedClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock stoppedClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func TestSleepContext(t *testing.T) {
+	for _, clock := range []Clock{NewWallClock(), NewMonotonicClock(), sleepOnlyClock{NewWallClock()}} {
+		assert.NoError(t, sleepContext(context.Background(), clock, time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		start := time.Now()
+		assert.Equal(t, context.DeadlineExceeded, sleepContext(ctx, clock, time.Hour))
+		assert.True(t, time.Since(start) < time.Second)
+		cancel()
+	}
+
+	// a fake clock does not advance once the context is done
+	clock := NewFakeClock(testStartTime)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, sleepContext(ctx, clock, time.Hour))
+	assert.Equal(t, testStartTime, clock.Now())
+}
+
+func TestWaitStrategyContext(t *testing.T) {
+	for _, strategy := range testWaitStrategies {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		start := time.Now()
+		assert.Equal(t, context.DeadlineExceeded, strategy.wait(ctx, NewWallClock(), time.Hour), "strategy %s", strategy)
+		assert.True(t, time.Since(start) < time.Second, "strategy %s", strategy)
+		cancel()
+	}
+}
+
+func TestGenerateContextCanceled(t *testing.T) {
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := gen.GenerateContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = gen.GenerateAsStreamContext(ctx, 1, make([]byte, gen.IDSize()), func(int, []byte) error {
+		assert.Fail(t, "Not expecting a callback")
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestGenerateContextWaitForTime(t *testing.T) {
+	waitForTime := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime, WithNotBeforeWait(2*time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := gen.GenerateContext(ctx, 1)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestGenerateContextNextInterval(t *testing.T) {
+	// the clock never reaches the next interval
+	for _, strategy := range testWaitStrategies {
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 2,
+			WithClock(stoppedClock{testStartTime}), WithWaitStrategy(strategy))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := gen.GenerateContext(ctx, 5)
+		assert.Equal(t, context.DeadlineExceeded, err, "strategy %s", strategy)
+		cancel()
+	}
+}
+
+func TestGenerateContextClockRegression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the context is canceled when the regression is reported, before waiting
+	gen, clock, _ := newRegressionTestGenerator(t, 5*time.Millisecond,
+		WithClockRegressionPolicy(ClockRegressionWait, time.Second),
+		WithClockRegressionHandler(func(ClockRegression) { cancel() }))
+	now := clock.Now()
+
+	_, err := gen.GenerateContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, now, clock.Now())
+}
+
+func TestGenerateAsStreamContextBetweenChunks(t *testing.T) {
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var called int
+	totalAllocated, err := gen.GenerateAsStreamContext(ctx, 10, make([]byte, 2*gen.IDSize()), func(int, []byte) error {
+		called++
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, called)
+	assert.Equal(t, 2, totalAllocated)
+}
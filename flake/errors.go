@@ -1,6 +1,10 @@
 package flake
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // ErrNoNetworkInterfaces occurs in the odd case where there are no network interfaces
 var ErrNoNetworkInterfaces = errors.New("No network interfaces are available")
@@ -14,9 +18,13 @@ var ErrNoHardwareAddresses = errors.New("No Hardware Adresses are available")
 var ErrInvalidSizeForHardwareAddress = errors.New("The requested size of the hardware ID is not supported by the provider")
 
 // ErrTooManyRequested occurs when the count passed to the Generate.Generate(int) func exceeds the maximum allowed
-// The maximum allowed is generally related to the maximum sequence number
+// The maximum allowed is DefaultMaxRequestSize unless set via WithMaxRequestSize
 var ErrTooManyRequested = errors.New("The # of ids requested exceeds the maximum amount for 1 request")
 
+// ErrIntervalExhausted occurs when a Backfiller is asked for more ids than the
+// sequence #'s remaining for the requested interval
+var ErrIntervalExhausted = errors.New("the sequence #'s remaining for the interval are insufficient")
+
 // ErrTimeIsMovingBackwards occurs when the clock moves backwards putting us into a position where we could
 // produce duplicate ids. That is obviously bad, and ID generation cannot resume until time catches up to
 // where we were
@@ -24,3 +32,102 @@ var ErrTimeIsMovingBackwards = errors.New("time is moving backwards. Cannot resu
 
 // ErrBufferTooSmall occurs when the user passes in a buffer that is too small to fit a single overt-flake ID
 var ErrBufferTooSmall = errors.New("the buffer is too small to hold an overt-flake ID")
+
+// ErrInvalidIDSyntax occurs when a string does not contain a well-formed
+// representation of an identifier (wrong characters, wrong length, empty, etc)
+var ErrInvalidIDSyntax = errors.New("invalid identifier syntax")
+
+// ErrIDOutOfRange occurs when a string represents a value that is too large to
+// fit within the bits of an identifier
+var ErrIDOutOfRange = errors.New("identifier value is out of range")
+
+// ErrInvalidIDLength occurs when the binary form of an identifier does not have
+// the expected # of bytes
+var ErrInvalidIDLength = errors.New("invalid identifier length")
+
+// ErrInvalidJSONID occurs when the JSON form of an identifier is not a JSON
+// string
+var ErrInvalidJSONID = errors.New("identifiers must be represented as JSON strings")
+
+// ErrNullID occurs when a NULL database value is scanned into an identifier
+// that is not nullable
+var ErrNullID = errors.New("cannot scan NULL into a non-nullable identifier")
+
+// ErrUnsupportedScanType occurs when a database value cannot be scanned into an
+// identifier because its type is incompatible with the storage mode
+var ErrUnsupportedScanType = errors.New("unsupported type for scanning an identifier")
+
+// ErrInvalidLayout occurs when a Layout does not describe a usable arrangement
+// of identifier fields. The specific problem is included in the returned error
+var ErrInvalidLayout = errors.New("invalid identifier layout")
+
+// ErrNodeOutOfRange occurs when the value that identifies a generator does not
+// fit in the node field (or a sub-field of it) of an identifier layout
+var ErrNodeOutOfRange = errors.New("the node value does not fit in the identifier layout")
+
+// ErrNodeCollision occurs when the node of a generator is already recorded for
+// another generator in a NodeRegistry
+var ErrNodeCollision = errors.New("the node is already in use by another generator")
+
+// ErrInvalidNodeRegistry occurs when a node registry file cannot be parsed
+var ErrInvalidNodeRegistry = errors.New("invalid node registry")
+
+// ErrTimeOutOfRange occurs when a time cannot be represented by the time field
+// of a Layout, because it is before the epoch or requires more bits than are
+// available
+var ErrTimeOutOfRange = errors.New("the time cannot be represented by the identifier layout")
+
+// ErrLanesNotSupported occurs when a Generator with more than 1 lane is created
+// on a platform where the process IDs of the lanes cannot be reserved
+var ErrLanesNotSupported = errors.New("lanes are not supported on this platform")
+
+// ErrNoLaneProcessIDs occurs when a Generator with more than 1 lane is created
+// but the OS threads reserved for the lanes do not have IDs that fit the 16
+// bits of a process ID
+var ErrNoLaneProcessIDs = errors.New("no thread IDs that fit 16 bits are available for the lanes")
+
+// ErrInvalidCheckpoint occurs when a checkpoint state file does not contain a
+// valid high-water mark
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// ErrNotYetAvailable occurs when ids are requested before the waitForTime of a
+// Generator. The error returned is a *NotYetAvailableError, which carries the
+// time remaining, and errors.Is(err, ErrNotYetAvailable) is true for it
+var ErrNotYetAvailable = errors.New("ids are not yet available")
+
+// NotYetAvailableError occurs when ids are requested before the waitForTime of
+// a Generator
+type NotYetAvailableError struct {
+	// Remaining is the time remaining until ids are available
+	Remaining time.Duration
+}
+
+// Error implements the error interface
+func (e *NotYetAvailableError) Error() string {
+	return fmt.Sprintf("%s (available in %s)", ErrNotYetAvailable, e.Remaining)
+}
+
+// Is reports whether target is ErrNotYetAvailable
+func (e *NotYetAvailableError) Is(target error) bool {
+	return target == ErrNotYetAvailable
+}
+
+// ParseError occurs when a string cannot be parsed into an identifier. Err is
+// always one of ErrInvalidIDSyntax or ErrIDOutOfRange so callers can use
+// errors.Is to determine the reason for the failure
+type ParseError struct {
+	// Input is the string that was being parsed
+	Input string
+	// Err is the reason the parse failed
+	Err error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("unable to parse %q as an identifier: %s", e.Input, e.Err)
+}
+
+// Unwrap returns the underlying reason for the parse failure
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
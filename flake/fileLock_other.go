@@ -0,0 +1,38 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package flake
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockFileTimeout is the longest lockFile waits for the lock
+const lockFileTimeout = 10 * time.Second
+
+// lockFile takes an exclusive lock on path by creating it, waiting up to
+// lockFileTimeout for another holder to remove it. The returned func releases
+// the lock
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockFileTimeout)
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock file %s", path)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,10 +1,19 @@
 package flake
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// readyPollInterval is the longest a generator waits before re-checking the
+// clock while waiting to become ready
+const readyPollInterval = 100 * time.Millisecond
+
 // generator is an implementtion of Generator and acts as a host for an
 // IDGenerator which creates and writes the identifiers for the Generator,
 // while the Generator worries about logic around time intervals and allocating
@@ -16,21 +25,145 @@ import (
 //
 //	- idGen is the IDGenerator that forms and writes the ID on the generator's
 //		behalf
+//	unit is the duration of an interval, as declared by the Layout of idGen.
+//		lastTime and notBefore are # of units since the Unix Epoch
+//	epoch and maxTime are the first and last intervals (units since the Unix
+//		Epoch) that the time field of the Layout of idGen can represent
 //	lastTime is the time interval when ids were last allocated. This value can
 // 		be saved periodically to know the minimum re-start time if the server
 //		crashes and needs to be restarted
-//	sequence is the sequence # for the current interval. It resets each
-//		millisecond (but only if 1 or more ids are being generated during
-//		the interval)
+//	sequence is the next sequence # for the current interval. It resets each
+//		interval (but only if 1 or more ids are being generated during
+//		the interval). When sequence > MaxSequenceNumber() the interval is
+//		exhausted
+//	clock is the source of time for the generator
+//	regressionPolicy, regressionMaxWait and regressionHandler determine how
+//		the generator reacts to the clock moving backwards
+//	maxRequestSize is the maximum # of ids that can be generated by 1 call to
+//		Generate or GenerateAsStream
+//	waitStrategy determines how callers wait for the next interval when the
+//		sequence #'s of the current interval are exhausted
+//	randomStart indicates that the sequence #'s of each interval start at a
+//		random offset rather than 0 (see WithRandomSequenceStart)
+//	notBefore is the waitForTime of the generator: no ids are generated for an
+//		earlier interval. Requests wait up to notBeforeMaxWait for it, and
+//		otherwise fail with a *NotYetAvailableError
+//	ready is closed once the clock reaches notBefore (see Ready)
+//	lockFree indicates that lastTime and sequence are not used, and that the
+//		time/sequence state is instead packed into state (see packState) and
+//		allocated with compare-and-swap rather than under mutex. state is the
+//		first field so that it is 64-bit aligned for atomic access
 type generator struct {
+	state    uint64
+	lockFree bool
+
 	idGen IDGenerator
+	unit  time.Duration
+
+	epoch   int64
+	maxTime int64
 
 	lastTime int64
 	sequence uint64
 
+	clock Clock
+
+	regressionPolicy  ClockRegressionPolicy
+	regressionMaxWait time.Duration
+	regressionHandler func(ClockRegression)
+
+	maxRequestSize int
+
+	waitStrategy WaitStrategy
+	randomStart  bool
+
+	notBefore        int64
+	notBeforeMaxWait time.Duration
+	ready            chan struct{}
+	readyOnce        sync.Once
+
 	mutex sync.Mutex
 }
 
+// NewGenerator creates an instance of generator (which implements Generator)
+// that hosts idGen. ids will not be generated for intervals before
+// waitForTime (milliseconds since the Unix Epoch). Until then requests fail
+// with ErrNotYetAvailable (see WithNotBeforeWait and Ready)
+//
+// Notes
+//
+// The interval that includes waitForTime is the first interval that ids are
+// generated for, whether or not the generator is lock-free. Previously the
+// interval of waitForTime was treated as exhausted, and the first ids were
+// generated for the interval after it
+func NewGenerator(idGen IDGenerator, waitForTime int64, opts ...GeneratorOption) Generator {
+	layout := idGen.Layout()
+	unit := layout.Unit()
+
+	// waitForTime is rounded up to the next whole interval
+	notBefore := (waitForTime*int64(time.Millisecond) + int64(unit) - 1) / int64(unit)
+
+	gen := &generator{
+		idGen:             idGen,
+		unit:              unit,
+		epoch:             layout.Epoch * int64(time.Millisecond) / int64(unit),
+		maxTime:           math.MaxInt64,
+		notBefore:         notBefore,
+		ready:             make(chan struct{}),
+		clock:             NewWallClock(),
+		regressionMaxWait: DefaultClockRegressionMaxWait,
+		maxRequestSize:    DefaultMaxRequestSize,
+	}
+
+	if layout.TimeBits < 63 {
+		gen.maxTime = gen.epoch + int64(bitMask(layout.TimeBits))
+	}
+
+	for _, opt := range opts {
+		opt(gen)
+	}
+
+	// The interval before notBefore is considered exhausted, so that the first
+	// allocation (for notBefore or later) starts a new interval. A lock-free
+	// state cannot represent an interval with no sequence #'s allocated, nor
+	// one before the epoch
+	gen.lastTime, gen.sequence = notBefore-1, gen.MaxSequenceNumber()+1
+	if gen.lastTime < gen.epoch {
+		gen.lastTime = gen.epoch
+	}
+
+	// fall back to the mutex if the state cannot be packed
+	if gen.lockFree && !SupportsLockFree(idGen) {
+		gen.lockFree = false
+	}
+
+	if gen.lockFree {
+		gen.state = gen.packState(gen.lastTime, gen.sequence)
+	}
+
+	return gen
+}
+
+// NewLockFreeGenerator creates an instance of generator (which implements
+// Generator) that hosts idGen and allocates sequence #'s with compare-and-swap
+// rather than under a mutex. ids will not be generated for intervals before
+// waitForTime (milliseconds since the Unix Epoch)
+//
+// Notes
+//
+// The time and sequence bits of idGen must fit in 64 bits (see
+// SupportsLockFree), otherwise the generator allocates under a mutex
+func NewLockFreeGenerator(idGen IDGenerator, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(idGen, waitForTime, append(opts, WithLockFree())...)
+}
+
+// SupportsLockFree determines if a lock-free generator can host idGen, which
+// requires the time bits of its Layout and its sequence bits to fit in the
+// 64-bit state of the generator (see NewLockFreeGenerator)
+func SupportsLockFree(idGen IDGenerator) bool {
+	return idGen.Layout().TimeBits+idGen.SequenceBitCount() <= 64
+}
+
 // IDSize implements IDGenerator.IDSize() and is a proxy to the underlying
 // IDGenerator
 func (gen *generator) IDSize() int {
@@ -71,28 +204,105 @@ func (gen *generator) Epoch() int64 {
 	return gen.idGen.Epoch()
 }
 
-// LastAllocatedTime is the last Unix Epoch value that one or more ids
-// are known to have been generated
+// Layout implements IDGenerator.Layout() and is a proxy to the underlying
+// IDGenerator
+func (gen *generator) Layout() Layout {
+	return gen.idGen.Layout()
+}
+
+// Decode provides access to the fields of an id created by the generator
+// using the Layout of its IDGenerator, or the IDGenerator itself if it
+// implements IDDecoder
+func (gen *generator) Decode(id []byte) (DecodedID, error) {
+	if decoder, ok := gen.idGen.(IDDecoder); ok {
+		return decoder.Decode(id)
+	}
+
+	return gen.Layout().Decode(id)
+}
+
+// Ready returns a channel that is closed once the clock reaches the
+// waitForTime of the generator
+func (gen *generator) Ready() <-chan struct{} {
+	gen.readyOnce.Do(func() {
+		go gen.waitUntilReady()
+	})
+
+	return gen.ready
+}
+
+// waitUntilReady closes ready once the clock reaches notBefore. Rather than
+// trusting a single sleep, the clock is re-checked at least every
+// readyPollInterval in case it is changed (or is not a wall clock)
+func (gen *generator) waitUntilReady() {
+	for {
+		remaining := gen.untilNotBefore()
+		if remaining <= 0 {
+			close(gen.ready)
+			return
+		}
+
+		if remaining > readyPollInterval {
+			remaining = readyPollInterval
+		}
+
+		time.Sleep(remaining)
+	}
+}
+
+// untilNotBefore is the time remaining until notBefore, according to the
+// generator Clock
+func (gen *generator) untilNotBefore() time.Duration {
+	return gen.intervalTime(gen.notBefore).Sub(gen.clock.Now())
+}
+
+// LastAllocatedTime is the last Unix Epoch value (in milliseconds) that one
+// or more ids are known to have been generated
 func (gen *generator) LastAllocatedTime() int64 {
-	gen.mutex.Lock()
-	defer gen.mutex.Unlock()
+	var lastTime int64
 
-	return gen.lastTime
+	if gen.lockFree {
+		lastTime, _ = gen.unpackState(atomic.LoadUint64(&gen.state))
+	} else {
+		gen.mutex.Lock()
+		lastTime = gen.lastTime
+		gen.mutex.Unlock()
+	}
+
+	return gen.intervalTime(lastTime).UnixNano() / int64(time.Millisecond)
 }
 
+// GenerateAsStream uses allocate to allocate as many ids as required, and
+// delivers them to callback each time buffer is filled. Requests larger than
+// the sequence space of an interval are fulfilled across successive intervals
 func (gen *generator) GenerateAsStream(count int, buffer []byte, callback func(int, []byte) error) (totalAllocated int, err error) {
+	return gen.GenerateAsStreamContext(context.Background(), count, buffer, callback)
+}
+
+// GenerateAsStreamContext is GenerateAsStream, but stops with ctx.Err() if
+// ctx is done while waiting or between callbacks
+func (gen *generator) GenerateAsStreamContext(ctx context.Context, count int, buffer []byte, callback func(int, []byte) error) (totalAllocated int, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
 	if len(buffer) < gen.IDSize() {
 		return 0, ErrBufferTooSmall
 	}
 
+	if count > gen.maxRequestSize {
+		return 0, ErrTooManyRequested
+	}
+
 	// while we still have ids to allocate/generate
 	for count > 0 {
 		var allocated uint64
 		var interval int64
+		var sequence uint64
 		var index int
 
 		// allocate as many ids as available up to count
-		allocated, interval, err = gen.allocate(count)
+		interval, sequence, allocated, err = gen.allocate(ctx, count)
 		if err != nil {
 			return
 		}
@@ -100,7 +310,7 @@ func (gen *generator) GenerateAsStream(count int, buffer []byte, callback func(i
 		// for each ID that was allocated, write the bytes for the ID to
 		// the results array
 		for j := uint64(0); j < allocated; j++ {
-			index += gen.SynthesizeID(buffer, index, interval, j)
+			index += gen.SynthesizeID(buffer, index, interval, sequence+j)
 
 			// buffer is full
 			if index >= len(buffer) {
@@ -114,6 +324,10 @@ func (gen *generator) GenerateAsStream(count int, buffer []byte, callback func(i
 
 				// back to beginning of the buffer
 				index = 0
+
+				if err = ctx.Err(); err != nil {
+					return
+				}
 			}
 		}
 
@@ -129,6 +343,10 @@ func (gen *generator) GenerateAsStream(count int, buffer []byte, callback func(i
 
 			// back to beginning of the buffer
 			index = 0
+
+			if err = ctx.Err(); err != nil {
+				return
+			}
 		}
 
 		count -= int(allocated)
@@ -138,76 +356,273 @@ func (gen *generator) GenerateAsStream(count int, buffer []byte, callback func(i
 }
 
 // Generate uses allocate to allocate as many ids as required, and writes
-// each id into a contiguous []byte
+// each id into a contiguous []byte. Requests larger than the sequence space of
+// an interval are fulfilled across successive intervals
 func (gen *generator) Generate(count int) (results []byte, err error) {
+	return gen.GenerateContext(context.Background(), count)
+}
+
+// GenerateContext is Generate, but fails with ctx.Err() if ctx is done while
+// waiting
+func (gen *generator) GenerateContext(ctx context.Context, count int) (results []byte, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if count > gen.maxRequestSize {
+		return nil, ErrTooManyRequested
+	}
+
 	// allocate a buffer that will hold count IDs
 	results = make([]byte, gen.IDSize()*count)
 
-	var allocated int
+	var index int
 
-	// use the stream API but because our buffer can hold all allocated ids we dont need
-	// to react in the callback
-	allocated, err = gen.GenerateAsStream(count, results, func(allocated int, ids []byte) error {
-		return nil
-	})
+	// allocations may be partial (when the sequence #'s for an interval run
+	// out) so keep allocating until the buffer is full
+	for index < len(results) {
+		var allocated uint64
+		var interval int64
+		var sequence uint64
+
+		interval, sequence, allocated, err = gen.allocate(ctx, count)
+		if err != nil {
+			// we do not want to return a partial result
+			return nil, err
+		}
 
-	// we do not want to return a partial result
-	if (allocated != count) || (err != nil) {
-		results = nil
+		for j := uint64(0); j < allocated; j++ {
+			index += gen.SynthesizeID(results, index, interval, sequence+j)
+		}
+
+		count -= int(allocated)
 	}
 
 	return
 }
 
 // allocate does all the magic of time and sequence management. It does not
-// perfomm the generation of the ids, but provides the data required to do so
-func (gen *generator) allocate(count int) (uint64, int64, error) {
-	if uint64(count) > gen.MaxSequenceNumber() {
-		return 0, 0, ErrTooManyRequested
+// perfomm the generation of the ids, but provides the data required to do so:
+// the interval, the first sequence # allocated and the # of sequence #'s
+// allocated (which may be less than count if the interval has insufficient
+// sequence #'s remaining). If ctx is done while waiting, ctx.Err() is returned
+func (gen *generator) allocate(ctx context.Context, count int) (int64, uint64, uint64, error) {
+	var waited, delayed time.Duration
+	var reported bool
+
+	for {
+		var alloc allocation
+		if gen.lockFree {
+			alloc = gen.tryAllocateAtomic(count)
+		} else {
+			alloc = gen.tryAllocate(count)
+		}
+
+		// report the regression (once per request) now that the lock is released
+		if alloc.regression != nil && !reported {
+			reported = true
+
+			if gen.regressionHandler != nil {
+				gen.regressionHandler(*alloc.regression)
+			}
+		}
+
+		// ids are not available yet, but will be in time
+		if _, ok := alloc.err.(*NotYetAvailableError); ok && delayed+alloc.wait <= gen.notBeforeMaxWait {
+			if err := sleepContext(ctx, gen.clock, alloc.wait); err != nil {
+				return 0, 0, 0, err
+			}
+
+			delayed += alloc.wait
+			continue
+		}
+
+		if alloc.err != nil || alloc.allocated > 0 {
+			return alloc.interval, alloc.sequence, alloc.allocated, alloc.err
+		}
+
+		// The sequence #'s for the interval are exhausted so wait (without the
+		// lock) for the next interval
+		if alloc.regression == nil {
+			if err := gen.waitStrategy.wait(ctx, gen.clock, alloc.wait); err != nil {
+				return 0, 0, 0, err
+			}
+
+			continue
+		}
+
+		// The clock is behind the last interval and the policy is to wait for it
+		// to catch up, but only if it can do so in the time remaining
+		if waited+alloc.wait > gen.regressionMaxWait {
+			return 0, 0, 0, ErrTimeIsMovingBackwards
+		}
+
+		if err := sleepContext(ctx, gen.clock, alloc.wait); err != nil {
+			return 0, 0, 0, err
+		}
+
+		waited += alloc.wait
 	}
+}
 
+// allocation is the outcome of a single attempt to allocate sequence #'s
+//
+//	interval, sequence and allocated are the interval, the first sequence #
+//		allocated and the # of sequence #'s allocated
+//	regression is set if the clock was observed moving backwards
+//	wait is the time to wait before trying again if 0 sequence #'s were
+//		allocated (and err is nil or a *NotYetAvailableError)
+type allocation struct {
+	interval   int64
+	sequence   uint64
+	allocated  uint64
+	regression *ClockRegression
+	wait       time.Duration
+	err        error
+}
+
+// tryAllocate makes a single attempt to allocate sequence #'s while holding
+// the generator lock
+func (gen *generator) tryAllocate(count int) allocation {
 	// We need to take the lock so we can manipulate the generator state
 	gen.mutex.Lock()
 	defer gen.mutex.Unlock()
 
-	// current time since Unix Epoch in milliseconds
-	current := timestamp()
+	alloc := gen.nextAllocation(gen.lastTime, gen.sequence, count)
+	if alloc.allocated > 0 {
+		gen.lastTime = alloc.interval
+		gen.sequence = alloc.sequence + alloc.allocated
+	}
 
-	// Is time going backwards? Thats a problem
-	if current < gen.lastTime {
-		return 0, 0, ErrTimeIsMovingBackwards
+	return alloc
+}
+
+// tryAllocateAtomic makes a single attempt to allocate sequence #'s without
+// taking the generator lock. The state is replaced with compare-and-swap, and
+// the allocation is retried whenever another caller changes it first
+func (gen *generator) tryAllocateAtomic(count int) allocation {
+	for {
+		state := atomic.LoadUint64(&gen.state)
+		lastTime, sequence := gen.unpackState(state)
+
+		alloc := gen.nextAllocation(lastTime, sequence, count)
+		if alloc.allocated == 0 {
+			return alloc
+		}
+
+		if atomic.CompareAndSwapUint64(&gen.state, state, gen.packState(alloc.interval, alloc.sequence+alloc.allocated)) {
+			return alloc
+		}
+	}
+}
+
+// packState packs the last interval (units since the Unix Epoch) and the next
+// sequence # for it into a single word. The interval is stored relative to
+// the epoch, so that it fits in the time bits of the Layout, and the last
+// sequence # allocated (rather than the next) is stored so that the sequence #
+// fits in SequenceBitCount() bits (see SupportsLockFree)
+func (gen *generator) packState(lastTime int64, sequence uint64) uint64 {
+	return uint64(lastTime-gen.epoch)<<gen.SequenceBitCount() | ((sequence - 1) & gen.MaxSequenceNumber())
+}
+
+// unpackState is the inverse of packState
+func (gen *generator) unpackState(state uint64) (int64, uint64) {
+	return int64(state>>gen.SequenceBitCount()) + gen.epoch, (state & gen.MaxSequenceNumber()) + 1
+}
+
+// nextAllocation determines the allocation of up to count sequence #'s given
+// the state of the generator (the last interval and the next sequence # for
+// it), without modifying the state. If the clock has moved backwards, the
+// regression is returned, and if the policy requires the caller to wait for
+// the clock to catch up, or the sequence #'s for the interval are exhausted,
+// 0 sequence #'s are allocated (with a nil error) along with the time to wait
+func (gen *generator) nextAllocation(lastTime int64, sequence uint64, count int) (alloc allocation) {
+	// current time since Unix Epoch in units
+	current := gen.now()
+
+	// Is it too soon?
+	if current < gen.notBefore {
+		alloc.wait = gen.untilNotBefore()
+		alloc.err = &NotYetAvailableError{Remaining: alloc.wait}
+		return
 	}
 
-	if gen.lastTime != current {
-		gen.sequence = 0
-	} else {
-		// When all the ids have been allocated for this interval then we end up
-		// here and we need to spin for the next cycle
-		if gen.sequence == 0 {
-			for current <= gen.lastTime {
-				current = timestamp()
+	// Is the time outside of what the Layout can represent? The time field
+	// would silently wrap
+	if current < gen.epoch || current > gen.maxTime {
+		alloc.err = ErrTimeOutOfRange
+		return
+	}
+
+	// Is time going backwards? Thats a problem
+	if current < lastTime {
+		alloc.regression = &ClockRegression{Last: lastTime, Current: current, Unit: gen.unit, Policy: gen.regressionPolicy}
+
+		switch {
+		case gen.regressionPolicy == ClockRegressionReuse && sequence <= gen.MaxSequenceNumber():
+			// carry on in the last interval
+			current = lastTime
+		case gen.regressionPolicy == ClockRegressionFail:
+			alloc.err = ErrTimeIsMovingBackwards
+			return
+		default:
+			// wait for the last interval, or the one after it if the last interval
+			// is exhausted
+			alloc.wait = alloc.regression.Drift()
+			if sequence > gen.MaxSequenceNumber() {
+				alloc.wait += gen.unit
 			}
+
+			return
 		}
 	}
 
-	gen.lastTime = current
+	if lastTime != current {
+		sequence = gen.firstSequence()
+	} else if sequence > gen.MaxSequenceNumber() {
+		// When all the ids have been allocated for this interval then we end up
+		// here and the caller needs to wait for the next interval
+		alloc.wait = gen.intervalTime(lastTime + 1).Sub(gen.clock.Now())
+		return
+	}
 
 	// allocated the request # of items, or whatever is remaining for this cycle
-	var allocated uint64
-	if uint64(count) > gen.sequence-gen.MaxSequenceNumber() {
-		allocated = gen.sequence - gen.MaxSequenceNumber()
-	} else {
-		allocated = uint64(count)
+	alloc.allocated = gen.MaxSequenceNumber() - sequence + 1
+	if uint64(count) < alloc.allocated {
+		alloc.allocated = uint64(count)
 	}
 
-	// advance the sequence for the # of items allocated
-	gen.sequence = (gen.sequence + allocated) & gen.SequenceBitMask()
+	alloc.interval = current
+	alloc.sequence = sequence
+
+	return
+}
+
+// firstSequence returns the first sequence # of a new interval, which is 0
+// unless randomStart is set, in which case it is a random # in the lower half
+// of the sequence #'s
+func (gen *generator) firstSequence() uint64 {
+	if !gen.randomStart {
+		return 0
+	}
+
+	var random [8]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return 0
+	}
+
+	// the # of sequence #'s is a power of 2, so there is no modulo bias
+	return binary.BigEndian.Uint64(random[:]) % ((gen.MaxSequenceNumber() >> 1) + 1)
+}
 
-	return allocated, current, nil
+// now returns the # of units that have passed since the unix epoch,
+// according to the generator Clock
+func (gen *generator) now() int64 {
+	return gen.clock.Now().UnixNano() / int64(gen.unit)
 }
 
-// timestamp returns the # of milliseconds that have passed since
-// the unix epoch
-func timestamp() int64 {
-	return time.Now().UnixNano() / 1e6
+// intervalTime returns the time at which interval (units since the Unix
+// Epoch) begins
+func (gen *generator) intervalTime(interval int64) time.Time {
+	return time.Unix(0, interval*int64(gen.unit))
 }
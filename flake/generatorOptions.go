@@ -0,0 +1,87 @@
+package flake
+
+import "time"
+
+// DefaultMaxRequestSize is the maximum # of ids that can be generated by 1
+// call to Generate or GenerateAsStream, unless otherwise specified
+const DefaultMaxRequestSize = 1 << 20
+
+// GeneratorOption configures optional behavior of a Generator created by
+// NewGenerator (or any of the generator constructors that accept options)
+type GeneratorOption func(*generator)
+
+// WithClock sets the Clock used by the generator to determine the current time
+// interval. The default is NewWallClock()
+func WithClock(clock Clock) GeneratorOption {
+	return func(gen *generator) {
+		gen.clock = clock
+	}
+}
+
+// WithClockRegressionPolicy sets the policy applied when the clock moves
+// backwards. maxWait is the longest a request will block waiting for the clock
+// to catch up (ClockRegressionWait and ClockRegressionReuse). A request fails
+// with ErrTimeIsMovingBackwards immediately if the clock is further behind than
+// maxWait
+func WithClockRegressionPolicy(policy ClockRegressionPolicy, maxWait time.Duration) GeneratorOption {
+	return func(gen *generator) {
+		gen.regressionPolicy = policy
+		gen.regressionMaxWait = maxWait
+	}
+}
+
+// WithClockRegressionHandler sets a callback that is invoked (without any
+// generator locks held) each time a request observes the clock moving
+// backwards, regardless of the policy
+func WithClockRegressionHandler(handler func(ClockRegression)) GeneratorOption {
+	return func(gen *generator) {
+		gen.regressionHandler = handler
+	}
+}
+
+// WithMaxRequestSize sets the maximum # of ids that can be generated by 1 call
+// to Generate or GenerateAsStream. Larger requests fail with ErrTooManyRequested
+func WithMaxRequestSize(maxRequestSize int) GeneratorOption {
+	return func(gen *generator) {
+		gen.maxRequestSize = maxRequestSize
+	}
+}
+
+// WithWaitStrategy sets how callers wait for the next interval when the
+// sequence #'s of the current interval are exhausted
+func WithWaitStrategy(strategy WaitStrategy) GeneratorOption {
+	return func(gen *generator) {
+		gen.waitStrategy = strategy
+	}
+}
+
+// WithLockFree packs the time/sequence state of the generator into a single
+// word that is updated with compare-and-swap, rather than under a mutex (see
+// NewLockFreeGenerator)
+func WithLockFree() GeneratorOption {
+	return func(gen *generator) {
+		gen.lockFree = true
+	}
+}
+
+// WithNotBeforeWait sets the longest a request will block waiting for the
+// waitForTime of the generator. If ids are not available within maxWait the
+// request fails immediately with a *NotYetAvailableError. By default requests
+// do not wait
+func WithNotBeforeWait(maxWait time.Duration) GeneratorOption {
+	return func(gen *generator) {
+		gen.notBeforeMaxWait = maxWait
+	}
+}
+
+// WithRandomSequenceStart starts the sequence #'s of each interval (including
+// the first interval of the generator) at a random offset in the lower half of
+// the sequence #'s, rather than at 0, so that the sequence # of an id does not
+// reveal how many ids were generated before it in the interval. Sequence #'s
+// still increase within an interval, and at least half of them remain
+// available in every interval
+func WithRandomSequenceStart() GeneratorOption {
+	return func(gen *generator) {
+		gen.randomStart = true
+	}
+}
@@ -1,6 +1,7 @@
 package flake
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"os"
@@ -122,3 +123,52 @@ func TestGenerateStreamIDs(t *testing.T) {
 	assert.Equal(t, 64, totalAllocated, "Expecting total # of ids generated to == %d, not %d", 64, totalAllocated)
 	assert.Equal(t, 1, called, "Expecting total # of callbacks to be %d, not %d", 1, called)
 }
+
+// assertIncreasing asserts that each id in ids is greater than the one before it
+func assertIncreasing(t *testing.T, idSize int, ids []byte) {
+	for index := idSize; index < len(ids); index += idSize {
+		if !assert.Equal(t, 1, bytes.Compare(ids[index:index+idSize], ids[index-idSize:index]), "id #%d", index/idSize) {
+			return
+		}
+	}
+}
+
+func TestGenerateAcrossIntervals(t *testing.T) {
+	gen := NewTwitterGenerator(1, 1, 0)
+
+	// more than 2 intervals worth of sequence #'s
+	count := 2*int(gen.MaxSequenceNumber()) + 100
+
+	ids, err := gen.Generate(count)
+	assert.NoError(t, err)
+	assert.Equal(t, count*gen.IDSize(), len(ids))
+	assertIncreasing(t, gen.IDSize(), ids)
+
+	var streamed []byte
+	buffer := make([]byte, gen.IDSize()*100)
+	totalAllocated, err := gen.GenerateAsStream(count, buffer, func(allocated int, ids []byte) error {
+		streamed = append(streamed, ids[:allocated*gen.IDSize()]...)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, count, totalAllocated)
+	assertIncreasing(t, gen.IDSize(), append(ids[len(ids)-gen.IDSize():], streamed...))
+}
+
+func TestGenerateMaxRequestSize(t *testing.T) {
+	gen := NewOvertFlakeGenerator53(testHardwareID, 42, 0, WithMaxRequestSize(10))
+
+	ids, err := gen.Generate(10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*gen.IDSize(), len(ids))
+
+	_, err = gen.Generate(11)
+	assert.Equal(t, ErrTooManyRequested, err)
+
+	buffer := make([]byte, gen.IDSize())
+	_, err = gen.GenerateAsStream(11, buffer, func(int, []byte) error {
+		assert.Fail(t, "Not expecting a callback")
+		return nil
+	})
+	assert.Equal(t, ErrTooManyRequested, err)
+}
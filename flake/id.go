@@ -0,0 +1,249 @@
+package flake
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ID is a value type representation of an overt-flake identifier. Unlike the
+// implementation of OvertFlakeID returned by NewOvertFlakeID, an ID does not
+// alias the buffer it was created from, is comparable (and therefore usable as
+// a map key), and its numeric accessors do not allocate.
+//
+// *ID implements OvertFlakeID, and the zero value is a valid target for
+// unmarshaling and scanning
+type ID [OvertFlakeIDLength]byte
+
+// NilID is the zero value of ID
+var NilID ID
+
+// IDFromBytes copies the OvertFlakeIDLength bytes of b into an ID
+func IDFromBytes(b []byte) (id ID, err error) {
+	if len(b) != OvertFlakeIDLength {
+		return id, ErrInvalidIDLength
+	}
+
+	copy(id[:], b)
+	return id, nil
+}
+
+// IDFromOvertFlakeID copies the bytes of an OvertFlakeID into an ID
+func IDFromOvertFlakeID(ofid OvertFlakeID) (id ID) {
+	copy(id[:], ofid.Bytes())
+	return id
+}
+
+// IDsFromBytes converts contiguous ids, as returned by Generator.Generate, into
+// a []ID using a single allocation. The length of ids must be a multiple of
+// OvertFlakeIDLength
+func IDsFromBytes(ids []byte) ([]ID, error) {
+	return AppendIDs(make([]ID, 0, len(ids)/OvertFlakeIDLength), ids)
+}
+
+// AppendIDs appends the contiguous ids in ids to dst, and returns the extended
+// slice. No allocation occurs if dst has sufficient capacity
+func AppendIDs(dst []ID, ids []byte) ([]ID, error) {
+	if len(ids)%OvertFlakeIDLength != 0 {
+		return dst, ErrInvalidIDLength
+	}
+
+	for index := 0; index < len(ids); index += OvertFlakeIDLength {
+		var id ID
+		copy(id[:], ids[index:index+OvertFlakeIDLength])
+		dst = append(dst, id)
+	}
+
+	return dst, nil
+}
+
+// OvertFlakeID returns a copy of the ID as an OvertFlakeID
+func (id ID) OvertFlakeID() OvertFlakeID {
+	return NewOvertFlakeID(id.Bytes())
+}
+
+// IsZero determines if the ID is the zero value (NilID)
+func (id ID) IsZero() bool {
+	return id == NilID
+}
+
+// Compare returns -1, 0 or 1 when id is less than, equal to, or greater than
+// other. The order is that of the bytes (and the generation order)
+func (id ID) Compare(other ID) int {
+	return bytes.Compare(id[:], other[:])
+}
+
+// Layout is the Layout used to extract the fields of the ID, which is always
+// DefaultOvertFlakeLayout. Use Layout.Decode for IDs created with other layouts
+func (id ID) Layout() Layout {
+	return DefaultOvertFlakeLayout
+}
+
+// Timestamp is when the ID was generated, and is the # of milliseconds since
+// the generator Epoch
+func (id ID) Timestamp() uint64 {
+	return id.Upper() >> 16
+}
+
+// Time is when the ID was generated, assuming the ID was generated using
+// OvertoneEpochMs
+func (id ID) Time() time.Time {
+	return DefaultOvertFlakeLayout.TimeForTimestamp(id.Timestamp())
+}
+
+// Sequence represents the Nth value created during a time interval
+func (id ID) Sequence() uint64 {
+	return id.Upper() & 0xFFFF
+}
+
+// Node is the value that identifies the generator of the ID, and is == MachineID()
+func (id ID) Node() uint64 {
+	return id.Lower()
+}
+
+// SequenceID represents the Nth value created during a time interval
+// (0 if the 1st interval generated)
+func (id ID) SequenceID() uint16 {
+	return uint16(id.Upper() & 0xFFFF)
+}
+
+// HardwareID is the HardwareID assigned by the generator
+func (id ID) HardwareID() HardwareID {
+	return append(HardwareID(nil), id[8:14]...)
+}
+
+// ProcessID is the processID assigned by the generator
+func (id ID) ProcessID() uint16 {
+	return uint16(id.Lower() & 0xFFFF)
+}
+
+// MachineID is the uint64 representation of HardwareID and ProcessID and is == Lower()
+func (id ID) MachineID() uint64 {
+	return id.Lower()
+}
+
+// Upper is the upper (most-signficant) bytes of the id represented as a uint64
+func (id ID) Upper() uint64 {
+	return binary.BigEndian.Uint64(id[0:8])
+}
+
+// Lower is the lower (least-signficant) bytes of the id represented as a uint64
+func (id ID) Lower() uint64 {
+	return binary.BigEndian.Uint64(id[8:16])
+}
+
+// Bytes returns a copy of the bytes of the ID
+func (id ID) Bytes() []byte {
+	return append([]byte(nil), id[:]...)
+}
+
+// ToBigInt converts the ID to a *big.Int
+func (id ID) ToBigInt() *big.Int {
+	return new(big.Int).SetBytes(id[:])
+}
+
+// String returns the big.Int string representation of the ID
+func (id ID) String() string {
+	return id.ToBigInt().String()
+}
+
+// Base62 returns the 22 character base62 (0-9A-Za-z) representation of the
+// ID. The result sorts in the same order as Bytes()
+func (id ID) Base62() string {
+	return base62Encoding.encode(id[:])
+}
+
+// Base58 returns the 22 character base58 (Bitcoin alphabet) representation of
+// the ID. The result sorts in the same order as Bytes()
+func (id ID) Base58() string {
+	return base58Encoding.encode(id[:])
+}
+
+// Base32 returns the 26 character Crockford base32 representation of the ID.
+// The result sorts in the same order as Bytes()
+func (id ID) Base32() string {
+	return crockfordBase32Encoding.encode(id[:])
+}
+
+// Hex returns the 32 character lower case hexadecimal representation of the ID
+func (id ID) Hex() string {
+	return encodeHex(id[:])
+}
+
+// UUID returns the ID formatted as a UUID (8-4-4-4-12 lower case hex)
+func (id ID) UUID() string {
+	return encodeUUID(id[:])
+}
+
+// Format implements fmt.Formatter, supporting %s, %v, %d, %q, %x and %X
+func (id ID) Format(f fmt.State, verb rune) {
+	formatID(f, verb, "flake.ID", id[:], id.String())
+}
+
+// MarshalText implements encoding.TextMarshaler using the decimal form of the ID
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler and accepts the decimal or
+// 0x prefixed hexadecimal form of an ID
+func (id *ID) UnmarshalText(text []byte) error {
+	return unmarshalTextID(text, id[:])
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (id ID) MarshalBinary() ([]byte, error) {
+	return id.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. data must be exactly
+// OvertFlakeIDLength bytes
+func (id *ID) UnmarshalBinary(data []byte) error {
+	if len(data) != OvertFlakeIDLength {
+		return ErrInvalidIDLength
+	}
+
+	copy(id[:], data)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the format specified by
+// JSONIDFormat
+func (id ID) MarshalJSON() ([]byte, error) {
+	return marshalJSONID(id[:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler using the format specified by
+// JSONIDFormat. null is a no-op
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+
+	var parsed ID
+	if err := unmarshalJSONID(data, parsed[:]); err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
+
+// Value implements driver.Valuer using DefaultSQLStorage
+func (id ID) Value() (driver.Value, error) {
+	return valueID(id[:], SQLStorageDefault)
+}
+
+// Scan implements sql.Scanner using DefaultSQLStorage
+func (id *ID) Scan(src interface{}) error {
+	var scanned ID
+	if err := scanID(src, SQLStorageDefault, scanned[:]); err != nil {
+		return err
+	}
+
+	*id = scanned
+	return nil
+}
@@ -0,0 +1,48 @@
+package flake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formatID implements fmt.Formatter for identifiers represented by the
+// big-endian bytes in id. The following verbs are supported
+//
+//	%s, %v, %d  the decimal representation (same as String())
+//	%q          the quoted decimal representation
+//	%x, %X      the zero-padded hexadecimal representation (%#x adds 0x)
+//
+// Width and the '-' flag are honored for all verbs
+func formatID(f fmt.State, verb rune, typeName string, id []byte, decimal string) {
+	var s string
+
+	switch verb {
+	case 's', 'v', 'd':
+		s = decimal
+	case 'q':
+		s = strconv.Quote(decimal)
+	case 'x':
+		s = encodeHex(id)
+	case 'X':
+		s = strings.ToUpper(encodeHex(id))
+	default:
+		fmt.Fprintf(f, "%%!%c(%s=%s)", verb, typeName, decimal)
+		return
+	}
+
+	if f.Flag('#') && (verb == 'x' || verb == 'X') {
+		s = "0" + string(verb) + s
+	}
+
+	if width, ok := f.Width(); ok && width > len(s) {
+		padding := strings.Repeat(" ", width-len(s))
+		if f.Flag('-') {
+			s += padding
+		} else {
+			s = padding + s
+		}
+	}
+
+	fmt.Fprint(f, s)
+}
@@ -0,0 +1,121 @@
+package flake
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// *ID must be usable anywhere an OvertFlakeID is expected
+var _ OvertFlakeID = (*ID)(nil)
+
+func TestIDMatchesOvertFlakeID(t *testing.T) {
+	gen := NewOvertoneEpochGenerator(testHardwareID)
+	idBytes, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	ofid := NewOvertFlakeID(idBytes)
+	id, err := IDFromBytes(idBytes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ofid.Timestamp(), id.Timestamp())
+	assert.Equal(t, ofid.SequenceID(), id.SequenceID())
+	assert.Equal(t, ofid.HardwareID(), id.HardwareID())
+	assert.Equal(t, ofid.ProcessID(), id.ProcessID())
+	assert.Equal(t, ofid.MachineID(), id.MachineID())
+	assert.Equal(t, ofid.Upper(), id.Upper())
+	assert.Equal(t, ofid.Lower(), id.Lower())
+	assert.Equal(t, ofid.Bytes(), id.Bytes())
+	assert.Equal(t, ofid.String(), id.String())
+	assert.Equal(t, ofid.Base62(), id.Base62())
+	assert.Equal(t, ofid.UUID(), id.UUID())
+	assert.Equal(t, fmt.Sprintf("%x", ofid), fmt.Sprintf("%x", id))
+
+	assert.Equal(t, id, IDFromOvertFlakeID(ofid))
+	assert.Equal(t, id, IDFromOvertFlakeID(id.OvertFlakeID()))
+
+	// the ID does not alias the bytes it was created from
+	idBytes[0] ^= 0xFF
+	assert.NotEqual(t, idBytes, id.Bytes())
+
+	_, err = IDFromBytes(idBytes[0:8])
+	assert.Equal(t, ErrInvalidIDLength, err)
+}
+
+func TestIDsFromBytes(t *testing.T) {
+	gen := NewOvertoneEpochGenerator(testHardwareID)
+	idBytes, err := gen.Generate(32)
+	assert.NoError(t, err)
+
+	ids, err := IDsFromBytes(idBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, 32, len(ids))
+
+	seen := make(map[ID]bool)
+	for i, id := range ids {
+		assert.Equal(t, idBytes[i*OvertFlakeIDLength:(i+1)*OvertFlakeIDLength], id.Bytes())
+		if i > 0 {
+			assert.Equal(t, 1, id.Compare(ids[i-1]))
+		}
+		seen[id] = true
+	}
+	assert.Equal(t, 32, len(seen))
+
+	_, err = IDsFromBytes(idBytes[1:])
+	assert.Equal(t, ErrInvalidIDLength, err)
+
+	// only the result slice is allocated
+	allocs := testing.AllocsPerRun(100, func() {
+		ids, _ = IDsFromBytes(idBytes)
+	})
+	assert.Equal(t, float64(1), allocs)
+
+	// and no allocations at all when the caller provides the capacity
+	allocs = testing.AllocsPerRun(100, func() {
+		ids, _ = AppendIDs(ids[:0], idBytes)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestIDAccessorsDoNotAllocate(t *testing.T) {
+	id := IDFromOvertFlakeID(MustParseOvertFlakeID("0x0123456789abcdef0123456789abcdef"))
+
+	var sum uint64
+	allocs := testing.AllocsPerRun(100, func() {
+		sum += id.Timestamp() + uint64(id.SequenceID()) + uint64(id.ProcessID()) + id.MachineID() + id.Upper() + id.Lower()
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestIDMarshaling(t *testing.T) {
+	id := IDFromOvertFlakeID(MustParseOvertFlakeID("0x0000000000000001000000000000002A"))
+
+	data, err := json.Marshal(struct {
+		ID ID `json:"id"`
+	}{id})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"18446744073709551658"}`, string(data))
+
+	var payload struct {
+		ID ID `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, id, payload.ID)
+
+	var parsed ID
+	assert.NoError(t, parsed.UnmarshalText([]byte("18446744073709551658")))
+	assert.Equal(t, id, parsed)
+
+	parsed = NilID
+	assert.True(t, parsed.IsZero())
+	assert.NoError(t, parsed.UnmarshalBinary(id.Bytes()))
+	assert.Equal(t, id, parsed)
+
+	parsed = NilID
+	value, err := id.Value()
+	assert.NoError(t, err)
+	assert.NoError(t, parsed.Scan(value))
+	assert.Equal(t, id, parsed)
+}
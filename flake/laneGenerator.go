@@ -0,0 +1,126 @@
+package flake
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// laneGenerator is an implementation of Generator that spreads requests
+// across multiple lanes, each of which is a Generator with its own time and
+// sequence state. A single Generator is limited to MaxSequenceNumber()+1 ids
+// per interval, so N lanes raise the limit N-fold, provided the ids from each
+// lane are distinct (for overt-flake ids, by using a distinct process ID).
+//
+// Each request is fulfilled by a single lane (in round-robin order), so ids
+// from 1 request are still strictly increasing, but ids from different
+// requests in the same interval are only ordered by the lane that generated
+// them
+//
+// All IDGenerator methods (and Decode) are provided by the first lane
+type laneGenerator struct {
+	Generator
+
+	lanes []Generator
+	next  uint32
+}
+
+// NewLaneGenerator creates an instance of laneGenerator (which implements
+// Generator) that spreads requests across lanes. The lanes must all produce
+// ids of the same size and layout, and must never produce the same id. A
+// single lane is returned as is
+func NewLaneGenerator(lanes ...Generator) Generator {
+	if len(lanes) == 1 {
+		return lanes[0]
+	}
+
+	return &laneGenerator{
+		Generator: lanes[0],
+		lanes:     lanes,
+	}
+}
+
+// NewOvertFlakeLaneGenerator creates a Generator with laneCount overt-flake
+// lanes for the current process. Lane 0 uses the ID of the process, and each
+// other lane uses the ID (which must fit 16 bits) of an OS thread that is
+// reserved for the life of the process, so the process IDs of the lanes are
+// distinct and are not the ID of another process on the host. As for any
+// overt-flake Generator, only the 16 least-significant bits of a process ID
+// are used, so a process whose ID exceeds 0xFFFF can share one.
+// ErrLanesNotSupported is returned if laneCount exceeds 1 on a platform other
+// than Linux, and ErrNoLaneProcessIDs if not enough threads have an ID that
+// fits 16 bits
+func NewOvertFlakeLaneGenerator(epoch int64, hardwareID HardwareID, laneCount int, waitForTime int64, opts ...GeneratorOption) (Generator, error) {
+	if laneCount < 1 {
+		laneCount = 1
+	}
+
+	processIDs, err := reserveLaneProcessIDs(laneCount)
+	if err != nil {
+		return nil, err
+	}
+
+	lanes := make([]Generator, laneCount)
+	for lane, processID := range processIDs {
+		lanes[lane] = NewOvertFlakeGenerator(epoch, hardwareID, processID, waitForTime, opts...)
+	}
+
+	return NewLaneGenerator(lanes...), nil
+}
+
+// NewOvertFlakeLaneGenerator53 creates a Generator with laneCount 53-bit
+// overt-flake lanes (see NewOvertFlakeLaneGenerator)
+func NewOvertFlakeLaneGenerator53(hardwareID HardwareID, laneCount int, waitForTime int64, opts ...GeneratorOption) (Generator, error) {
+	if laneCount < 1 {
+		laneCount = 1
+	}
+
+	processIDs, err := reserveLaneProcessIDs(laneCount)
+	if err != nil {
+		return nil, err
+	}
+
+	lanes := make([]Generator, laneCount)
+	for lane, processID := range processIDs {
+		lanes[lane] = NewOvertFlakeGenerator53(hardwareID, processID, waitForTime, opts...)
+	}
+
+	return NewLaneGenerator(lanes...), nil
+}
+
+// LastAllocatedTime is the latest time any lane allocated ids
+func (gen *laneGenerator) LastAllocatedTime() int64 {
+	lastTime := gen.lanes[0].LastAllocatedTime()
+
+	for _, lane := range gen.lanes[1:] {
+		if laneTime := lane.LastAllocatedTime(); laneTime > lastTime {
+			lastTime = laneTime
+		}
+	}
+
+	return lastTime
+}
+
+// Generate generates count ids using the next lane
+func (gen *laneGenerator) Generate(count int) ([]byte, error) {
+	return gen.nextLane().Generate(count)
+}
+
+// GenerateAsStream generates count ids, in chunks, using the next lane
+func (gen *laneGenerator) GenerateAsStream(count int, buffer []byte, callback func(int, []byte) error) (int, error) {
+	return gen.nextLane().GenerateAsStream(count, buffer, callback)
+}
+
+// GenerateContext generates count ids using the next lane
+func (gen *laneGenerator) GenerateContext(ctx context.Context, count int) ([]byte, error) {
+	return gen.nextLane().GenerateContext(ctx, count)
+}
+
+// GenerateAsStreamContext generates count ids, in chunks, using the next lane
+func (gen *laneGenerator) GenerateAsStreamContext(ctx context.Context, count int, buffer []byte, callback func(int, []byte) error) (int, error) {
+	return gen.nextLane().GenerateAsStreamContext(ctx, count, buffer, callback)
+}
+
+// nextLane selects the lane for a request in round-robin order
+func (gen *laneGenerator) nextLane() Generator {
+	return gen.lanes[int(atomic.AddUint32(&gen.next, 1)%uint32(len(gen.lanes)))]
+}
@@ -0,0 +1,133 @@
+//go:build linux
+// +build linux
+
+package flake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertFlakeLaneGenerator(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen, err := NewOvertFlakeLaneGenerator(UnixEpochMs, testHardwareID, 4, 0, WithClock(clock))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	lanes := gen.(*laneGenerator).lanes
+	assert.Len(t, lanes, 4)
+
+	// lane 0 uses the process ID, and the other lanes use the IDs of threads
+	// of this process, which no other process can have
+	processIDs := make(map[int]bool)
+	for lane, laneGen := range lanes {
+		processID := laneGen.IDGenerator().(OvertFlakeIDGenerator).ProcessID()
+		if lane == 0 {
+			assert.Equal(t, os.Getpid()&0xFFFF, processID)
+		} else {
+			_, err := os.Stat(fmt.Sprintf("/proc/self/task/%d", processID))
+			assert.NoError(t, err)
+		}
+
+		assert.False(t, processIDs[processID])
+		processIDs[processID] = true
+	}
+
+	// the clock never moves, yet 4 intervals worth of ids are generated
+	perInterval := int(gen.MaxSequenceNumber()) + 1
+	seen := make(map[ID]bool)
+
+	for lane := 0; lane < 4; lane++ {
+		ids, err := gen.Generate(perInterval)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		for _, decoded := range decodeAll(t, gen, ids) {
+			assert.True(t, testStartTime.Equal(decoded.Time()))
+
+			id := IDFromOvertFlakeID(decoded.(OvertFlakeID))
+			assert.False(t, seen[id])
+			seen[id] = true
+		}
+	}
+
+	assert.Equal(t, 4*perInterval, len(seen))
+	assert.Equal(t, testStartTime.UnixNano()/1e6, gen.LastAllocatedTime())
+}
+
+func TestLaneProcessIDsFit16Bits(t *testing.T) {
+	threadID := gettid
+	defer func() { gettid = threadID }()
+
+	// the first thread has an ID that would be truncated to another ID
+	var calls int
+	gettid = func() int {
+		calls++
+		if calls == 1 {
+			return 70000
+		}
+		return threadID()
+	}
+
+	processIDs, err := reserveLaneProcessIDs(3)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, processIDs, 3)
+	for _, processID := range processIDs[1:] {
+		assert.NotEqual(t, 70000, processID)
+		assert.True(t, processID <= 0xFFFF)
+	}
+
+	// and no thread has an ID that fits
+	gettid = func() int { return 70000 }
+
+	_, err = reserveLaneProcessIDs(2)
+	assert.True(t, errors.Is(err, ErrNoLaneProcessIDs))
+}
+
+func TestLaneGeneratorConcurrency(t *testing.T) {
+	const workers = 8
+	const requests = 100
+
+	gen, err := NewOvertFlakeLaneGenerator53(testHardwareID, 3, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	seen := make(map[string]bool)
+
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for request := 0; request < requests; request++ {
+				ids, err := gen.Generate(5)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				mutex.Lock()
+				for index := 0; index < len(ids); index += gen.IDSize() {
+					id := string(ids[index : index+gen.IDSize()])
+					assert.False(t, seen[id])
+					seen[id] = true
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, workers*requests*5, len(seen))
+}
@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package flake
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// laneThreadAttempts is the most threads reserveLaneProcessIDs tries for each
+// lane before it gives up
+const laneThreadAttempts = 16
+
+// gettid returns the ID of the current OS thread
+var gettid = syscall.Gettid
+
+// reserveLaneProcessIDs returns laneCount distinct process IDs. The first is
+// the ID of the process, and the rest are the IDs of OS threads that are
+// locked for the life of the process (Linux allocates thread IDs from the same
+// space as process IDs), so they cannot be the ID of another process. Only
+// thread IDs that fit 16 bits (the process ID of an overt-flake id) are used,
+// and ErrNoLaneProcessIDs is returned if not enough threads have one
+func reserveLaneProcessIDs(laneCount int) ([]int, error) {
+	pid := os.Getpid()
+	processIDs := []int{pid}
+
+	// threads that cannot be used (including the main thread, which has the ID
+	// of the process) are held, rather than reserved, until the other threads
+	// are reserved so that they are not tried again
+	threadID := make(chan int)
+	usable := make(chan bool)
+	release := make(chan struct{})
+	defer close(release)
+
+	for attempts := laneCount * laneThreadAttempts; len(processIDs) < laneCount; attempts-- {
+		if attempts == 0 {
+			return nil, ErrNoLaneProcessIDs
+		}
+
+		go func() {
+			runtime.LockOSThread()
+			threadID <- gettid()
+
+			if !<-usable {
+				<-release
+				runtime.UnlockOSThread()
+				return
+			}
+
+			// the thread (and its ID) is held until the process exits
+			select {}
+		}()
+
+		tid := <-threadID
+		ok := tid != pid && tid <= 0xFFFF && tid != pid&0xFFFF
+		usable <- ok
+
+		if ok {
+			processIDs = append(processIDs, tid)
+		}
+	}
+
+	return processIDs, nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package flake
+
+import "os"
+
+// reserveLaneProcessIDs returns the ID of the process when laneCount is 1.
+// Otherwise ErrLanesNotSupported is returned, as there is no way to reserve
+// more process IDs on this platform
+func reserveLaneProcessIDs(laneCount int) ([]int, error) {
+	if laneCount > 1 {
+		return nil, ErrLanesNotSupported
+	}
+
+	return []int{os.Getpid()}, nil
+}
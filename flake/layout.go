@@ -0,0 +1,419 @@
+package flake
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// FieldOrder determines the order, from most to least significant, of the
+// sequence and node fields of an identifier. The time field is always the
+// most significant field as it is primarily responsible for the sort order
+type FieldOrder int
+
+const (
+	// TimeSequenceNode orders fields as [time][sequence][node], which is the
+	// order used by overt-flake identifiers
+	TimeSequenceNode FieldOrder = iota
+	// TimeNodeSequence orders fields as [time][node][sequence], which is the
+	// order used by Twitter Snowflake identifiers
+	TimeNodeSequence
+)
+
+// String returns the name of the field order
+func (order FieldOrder) String() string {
+	switch order {
+	case TimeSequenceNode:
+		return "timeSequenceNode"
+	case TimeNodeSequence:
+		return "timeNodeSequence"
+	}
+
+	return "unknown"
+}
+
+// ParseFieldOrder converts the name of a field order (timeSequenceNode or
+// timeNodeSequence, case insensitive) to a FieldOrder
+func ParseFieldOrder(name string) (FieldOrder, error) {
+	switch strings.ToLower(name) {
+	case "timesequencenode":
+		return TimeSequenceNode, nil
+	case "timenodesequence":
+		return TimeNodeSequence, nil
+	}
+
+	return TimeSequenceNode, fmt.Errorf("unknown field order: %s", name)
+}
+
+// Layout describes how the time, sequence and node fields are arranged within
+// an identifier. Fields are packed into the least-significant bits of the
+// (big endian) identifier in the order specified by Order, and any remaining
+// most-significant bits are unused (always 0).
+//
+// The node field is whatever identifies the generator, ie. the hardware ID
+// and process ID of an overt-flake identifier, or the data center and machine
+// IDs of a Twitter Snowflake identifier
+type Layout struct {
+	// IDSize is the size, in bytes, of the identifier (at most 16)
+	IDSize int
+	// Epoch is the # of milliseconds elapsed between the Unix Epoch and the
+	// value 0 of the time field
+	Epoch int64
+	// TimeBits is the # of bits used for the time field
+	TimeBits uint64
+	// SequenceBits is the # of bits used for the per-interval sequence #
+	SequenceBits uint64
+	// NodeBits is the # of bits used to identify the generator
+	NodeBits uint64
+	// Order is the order of the sequence and node fields
+	Order FieldOrder
+	// TimeUnit is the duration of 1 interval of the time field, and must
+	// divide 1 second evenly (ie. time.Microsecond, time.Millisecond or
+	// 10 * time.Millisecond). 0 means time.Millisecond
+	TimeUnit time.Duration
+}
+
+// NewOvertFlakeLayout creates the Layout of an overt-flake identifier for the
+// given epoch and # of sequence bits
+func NewOvertFlakeLayout(epoch int64, sequenceBits uint64) Layout {
+	return Layout{
+		IDSize:       OvertFlakeIDLength,
+		Epoch:        epoch,
+		TimeBits:     64 - sequenceBits,
+		SequenceBits: sequenceBits,
+		NodeBits:     64,
+		Order:        TimeSequenceNode,
+	}
+}
+
+// DefaultOvertFlakeLayout is the Layout of an overt-flake identifier created by
+// NewOvertoneEpochGenerator, and is assumed by NewOvertFlakeID and ID
+var DefaultOvertFlakeLayout = NewOvertFlakeLayout(OvertoneEpochMs, DefaultSequenceBits)
+
+// OvertFlake53Layout is the Layout of the 53-bit overt-flake variant (see
+// NewOvertFlakeGenerator53)
+var OvertFlake53Layout = Layout{
+	IDSize:       OvertFlakeIDLength,
+	Epoch:        OvertoneEpochMs,
+	TimeBits:     53 - SequenceBits53,
+	SequenceBits: SequenceBits53,
+	NodeBits:     64,
+	Order:        TimeSequenceNode,
+}
+
+// TwitterFlakeLayout is the Layout of a Twitter Snowflake identifier
+var TwitterFlakeLayout = Layout{
+	IDSize:       8,
+	Epoch:        SnowflakeEpochMs,
+	TimeBits:     41,
+	SequenceBits: 12,
+	NodeBits:     10,
+	Order:        TimeNodeSequence,
+}
+
+// Validate determines if the layout is usable, returning an error wrapping
+// ErrInvalidLayout if it is not
+func (layout Layout) Validate() error {
+	if layout.IDSize < 1 || layout.IDSize > 16 {
+		return fmt.Errorf("%w: the ID size must be between 1 and 16 bytes, not %d", ErrInvalidLayout, layout.IDSize)
+	}
+
+	if layout.TimeBits == 0 || layout.TimeBits > 64 {
+		return fmt.Errorf("%w: the time field must be between 1 and 64 bits, not %d", ErrInvalidLayout, layout.TimeBits)
+	}
+
+	// the # of sequence #'s (MaxSequenceNumber() + 1) must fit in 64 bits
+	if layout.SequenceBits >= 64 {
+		return fmt.Errorf("%w: the sequence field must be less than 64 bits, not %d", ErrInvalidLayout, layout.SequenceBits)
+	}
+
+	if layout.NodeBits > 64 {
+		return fmt.Errorf("%w: the node field cannot exceed 64 bits (%d)", ErrInvalidLayout, layout.NodeBits)
+	}
+
+	if layout.TimeUnit < 0 || layout.TimeUnit > time.Second || (layout.TimeUnit > 0 && time.Second%layout.TimeUnit != 0) {
+		return fmt.Errorf("%w: the time unit must divide 1 second evenly, not %s", ErrInvalidLayout, layout.TimeUnit)
+	}
+
+	if layout.Order != TimeSequenceNode && layout.Order != TimeNodeSequence {
+		return fmt.Errorf("%w: unknown field order %d", ErrInvalidLayout, layout.Order)
+	}
+
+	if total := layout.TimeBits + layout.SequenceBits + layout.NodeBits; total > uint64(layout.IDSize*8) {
+		return fmt.Errorf("%w: %d bits of fields do not fit in %d bytes", ErrInvalidLayout, total, layout.IDSize)
+	}
+
+	return nil
+}
+
+// Bits returns the total # of bits used by the fields of the layout
+func (layout Layout) Bits() uint64 {
+	return layout.TimeBits + layout.SequenceBits + layout.NodeBits
+}
+
+// SequenceBitMask returns the mask for the sequence field (also the maximum
+// sequence #)
+func (layout Layout) SequenceBitMask() uint64 {
+	return bitMask(layout.SequenceBits)
+}
+
+// timeShift is the bit offset (from the LSB) of the time field
+func (layout Layout) timeShift() uint64 {
+	return layout.SequenceBits + layout.NodeBits
+}
+
+// sequenceShift is the bit offset (from the LSB) of the sequence field
+func (layout Layout) sequenceShift() uint64 {
+	if layout.Order == TimeNodeSequence {
+		return 0
+	}
+
+	return layout.NodeBits
+}
+
+// nodeShift is the bit offset (from the LSB) of the node field
+func (layout Layout) nodeShift() uint64 {
+	if layout.Order == TimeNodeSequence {
+		return layout.SequenceBits
+	}
+
+	return 0
+}
+
+// Unit returns the duration of 1 interval of the time field (TimeUnit, or
+// time.Millisecond if TimeUnit is 0)
+func (layout Layout) Unit() time.Duration {
+	if layout.TimeUnit == 0 {
+		return time.Millisecond
+	}
+
+	return layout.TimeUnit
+}
+
+// unitsPerSecond is the # of intervals of the time field per second
+func (layout Layout) unitsPerSecond() uint64 {
+	return uint64(time.Second / layout.Unit())
+}
+
+// Timestamp extracts the time field from id, which is the # of intervals since
+// the layout Epoch
+func (layout Layout) Timestamp(id []byte) uint64 {
+	hi, lo := readUint128(id)
+	return extractBits(hi, lo, layout.timeShift(), layout.TimeBits)
+}
+
+// Sequence extracts the sequence field from id
+func (layout Layout) Sequence(id []byte) uint64 {
+	hi, lo := readUint128(id)
+	return extractBits(hi, lo, layout.sequenceShift(), layout.SequenceBits)
+}
+
+// Node extracts the node field from id
+func (layout Layout) Node(id []byte) uint64 {
+	hi, lo := readUint128(id)
+	return extractBits(hi, lo, layout.nodeShift(), layout.NodeBits)
+}
+
+// Time extracts the time field from id and converts it to a time.Time
+func (layout Layout) Time(id []byte) time.Time {
+	return layout.TimeForTimestamp(layout.Timestamp(id))
+}
+
+// TimeForTimestamp converts the value of a time field to a time.Time
+func (layout Layout) TimeForTimestamp(timestamp uint64) time.Time {
+	// seconds and nanoseconds are calculated separately so that large
+	// timestamps do not overflow
+	perSecond := layout.unitsPerSecond()
+	seconds := layout.Epoch/1000 + int64(timestamp/perSecond)
+	nanos := (layout.Epoch%1000)*int64(time.Millisecond) + int64(timestamp%perSecond)*int64(layout.Unit())
+
+	return time.Unix(seconds, nanos)
+}
+
+// TimestampForTime converts t to the value of the time field. t is truncated to
+// the time unit. ErrTimeOutOfRange is returned if t is before the Epoch or
+// is too far after it to be represented by TimeBits
+func (layout Layout) TimestampForTime(t time.Time) (uint64, error) {
+	seconds := t.Unix() - layout.Epoch/1000
+	nanos := int64(t.Nanosecond()) - (layout.Epoch%1000)*int64(time.Millisecond)
+	if nanos < 0 {
+		seconds--
+		nanos += int64(time.Second)
+	}
+
+	if seconds < 0 {
+		return 0, ErrTimeOutOfRange
+	}
+
+	perSecond := layout.unitsPerSecond()
+	if uint64(seconds) > bitMask(layout.TimeBits)/perSecond {
+		return 0, ErrTimeOutOfRange
+	}
+
+	timestamp := uint64(seconds)*perSecond + uint64(nanos)/uint64(layout.Unit())
+	if timestamp > bitMask(layout.TimeBits) {
+		return 0, ErrTimeOutOfRange
+	}
+
+	return timestamp, nil
+}
+
+// MinIDForTime returns the smallest id, for the layout, that could be generated
+// at time t (all sequence and node bits are 0). Together with MaxIDForTime it
+// can be used to select the ids generated during a time range, ie.
+//
+//	WHERE id BETWEEN layout.MinIDForTime(start) AND layout.MaxIDForTime(end)
+func (layout Layout) MinIDForTime(t time.Time) ([]byte, error) {
+	return layout.boundaryIDForTime(t, 0)
+}
+
+// MaxIDForTime returns the largest id, for the layout, that could be generated
+// at time t (all sequence and node bits are 1)
+func (layout Layout) MaxIDForTime(t time.Time) ([]byte, error) {
+	return layout.boundaryIDForTime(t, 0xFFFFFFFFFFFFFFFF)
+}
+
+// boundaryIDForTime creates an id for t, filling the sequence and node fields
+// with the bits of fill
+func (layout Layout) boundaryIDForTime(t time.Time, fill uint64) ([]byte, error) {
+	timestamp, err := layout.TimestampForTime(t)
+	if err != nil {
+		return nil, err
+	}
+
+	hi, lo := insertBits(0, 0, layout.timeShift(), layout.TimeBits, timestamp)
+	hi, lo = insertBits(hi, lo, layout.sequenceShift(), layout.SequenceBits, fill)
+	hi, lo = insertBits(hi, lo, layout.nodeShift(), layout.NodeBits, fill)
+
+	id := make([]byte, layout.IDSize)
+	writeUint128(id, hi, lo)
+
+	return id, nil
+}
+
+// Decode wraps id in a DecodedID whose accessors extract fields according to
+// the layout. Overt-flake layouts (16 bytes, [time][sequence][node], 64 node
+// bits) decode to a value that also implements OvertFlakeID. The bytes of id
+// are not copied
+func (layout Layout) Decode(id []byte) (DecodedID, error) {
+	if len(id) != layout.IDSize {
+		return nil, ErrInvalidIDLength
+	}
+
+	if layout.isOvertFlake() {
+		return NewOvertFlakeIDWithLayout(id, layout), nil
+	}
+
+	return &layoutID{layout: layout, idBytes: id}, nil
+}
+
+// isOvertFlake determines if the layout is compatible with OvertFlakeID
+func (layout Layout) isOvertFlake() bool {
+	return layout.IDSize == OvertFlakeIDLength && layout.Order == TimeSequenceNode && layout.NodeBits == 64
+}
+
+// layoutID is a DecodedID for identifiers that have no specialized
+// representation
+type layoutID struct {
+	layout  Layout
+	idBytes []byte
+}
+
+func (id *layoutID) Layout() Layout {
+	return id.layout
+}
+
+func (id *layoutID) Timestamp() uint64 {
+	return id.layout.Timestamp(id.idBytes)
+}
+
+func (id *layoutID) Time() time.Time {
+	return id.layout.Time(id.idBytes)
+}
+
+func (id *layoutID) Sequence() uint64 {
+	return id.layout.Sequence(id.idBytes)
+}
+
+func (id *layoutID) Node() uint64 {
+	return id.layout.Node(id.idBytes)
+}
+
+func (id *layoutID) Bytes() []byte {
+	return id.idBytes
+}
+
+func (id *layoutID) String() string {
+	return new(big.Int).SetBytes(id.idBytes).String()
+}
+
+// bitMask returns a mask of the n least-significant bits
+func bitMask(n uint64) uint64 {
+	if n >= 64 {
+		return 0xFFFFFFFFFFFFFFFF
+	}
+
+	return (uint64(1) << n) - 1
+}
+
+// readUint128 reads a big-endian value of up to 16 bytes as a 128-bit value
+func readUint128(b []byte) (hi, lo uint64) {
+	var padded [16]byte
+	copy(padded[16-len(b):], b)
+
+	return binary.BigEndian.Uint64(padded[0:8]), binary.BigEndian.Uint64(padded[8:16])
+}
+
+// writeUint128 writes the len(b) least-significant bytes of a 128-bit value to
+// b (big-endian)
+func writeUint128(b []byte, hi, lo uint64) {
+	var padded [16]byte
+	binary.BigEndian.PutUint64(padded[0:8], hi)
+	binary.BigEndian.PutUint64(padded[8:16], lo)
+
+	copy(b, padded[16-len(b):])
+}
+
+// extractBits extracts width bits at offset shift (from the LSB) of a 128-bit
+// value
+func extractBits(hi, lo, shift, width uint64) uint64 {
+	if width == 0 {
+		return 0
+	}
+
+	var value uint64
+	switch {
+	case shift >= 64:
+		value = hi >> (shift - 64)
+	case shift == 0:
+		value = lo
+	default:
+		value = lo>>shift | hi<<(64-shift)
+	}
+
+	return value & bitMask(width)
+}
+
+// insertBits ORs the width least-significant bits of value into a 128-bit
+// value at offset shift (from the LSB)
+func insertBits(hi, lo, shift, width, value uint64) (uint64, uint64) {
+	if width == 0 {
+		return hi, lo
+	}
+
+	value &= bitMask(width)
+
+	switch {
+	case shift >= 64:
+		hi |= value << (shift - 64)
+	case shift == 0:
+		lo |= value
+	default:
+		lo |= value << shift
+		hi |= value >> (64 - shift)
+	}
+
+	return hi, lo
+}
@@ -0,0 +1,203 @@
+package flake
+
+import (
+	"fmt"
+	"time"
+)
+
+// NodeSource determines the value of the node field of the identifiers created
+// by a layout synthesizer (see NewLayoutSynthesizer)
+type NodeSource interface {
+	// NodeValue returns the value of a node field of the given # of bits, or an
+	// error wrapping ErrNodeOutOfRange if the value does not fit
+	NodeValue(bits uint64) (uint64, error)
+}
+
+type fixedNodeSource struct {
+	value uint64
+}
+
+// NewFixedNodeSource creates a NodeSource for a configured value (such as a
+// worker ID assigned by a deployment system)
+func NewFixedNodeSource(value uint64) NodeSource {
+	return &fixedNodeSource{
+		value: value,
+	}
+}
+
+func (fixed *fixedNodeSource) NodeValue(bits uint64) (uint64, error) {
+	if fixed.value > bitMask(bits) {
+		return 0, fmt.Errorf("%w: %d requires more than %d bits", ErrNodeOutOfRange, fixed.value, bits)
+	}
+
+	return fixed.value, nil
+}
+
+type processNodeSource struct {
+	processID int
+}
+
+// NewProcessNodeSource creates a NodeSource for a process ID. It is only unique
+// for processes on the same host
+func NewProcessNodeSource(processID int) NodeSource {
+	return &processNodeSource{
+		processID: processID,
+	}
+}
+
+func (process *processNodeSource) NodeValue(bits uint64) (uint64, error) {
+	if process.processID < 0 || uint64(process.processID) > bitMask(bits) {
+		return 0, fmt.Errorf("%w: process id %d requires more than %d bits", ErrNodeOutOfRange, process.processID, bits)
+	}
+
+	return uint64(process.processID), nil
+}
+
+type hardwareNodeSource struct {
+	hardwareID HardwareID
+	processID  int
+}
+
+// NewHardwareNodeSource creates a NodeSource that places hardwareID in the
+// most-significant bits of the node field, and the process ID in the remaining
+// bits, as an overt-flake identifier does. The node field must be large enough
+// for hardwareID (at most 8 bytes). As with overt-flake identifiers, only the
+// least-significant bits of the process ID that fit are used
+func NewHardwareNodeSource(hardwareID HardwareID, processID int) NodeSource {
+	return &hardwareNodeSource{
+		hardwareID: hardwareID,
+		processID:  processID,
+	}
+}
+
+func (hardware *hardwareNodeSource) NodeValue(bits uint64) (uint64, error) {
+	hidBits := uint64(len(hardware.hardwareID) * 8)
+	if len(hardware.hardwareID) == 0 || hidBits > bits {
+		return 0, fmt.Errorf("%w: a %d byte hardware id does not fit in %d bits", ErrNodeOutOfRange, len(hardware.hardwareID), bits)
+	}
+
+	var value uint64
+	for _, b := range hardware.hardwareID {
+		value = value<<8 | uint64(b)
+	}
+
+	pidBits := bits - hidBits
+	if pidBits == 0 {
+		return value, nil
+	}
+
+	return value<<pidBits | (uint64(hardware.processID) & bitMask(pidBits)), nil
+}
+
+type layoutSynthesizer struct {
+	layout Layout
+	node   uint64
+	epoch  int64
+}
+
+// NewLayoutSynthesizer creates an IDGenerator that synthesizes 64, 96 or 128-bit
+// identifiers with the fields described by layout. The node field of every
+// identifier is the value provided by source. An error wrapping
+// ErrInvalidLayout or ErrNodeOutOfRange is returned if layout is not usable
+// (including an epoch in the future, or a time field too small for the current
+// time) or the node value does not fit
+func NewLayoutSynthesizer(layout Layout, source NodeSource) (IDGenerator, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch layout.IDSize {
+	case 8, 12, 16:
+	default:
+		return nil, fmt.Errorf("%w: the ID size must be 64, 96 or 128 bits, not %d", ErrInvalidLayout, layout.IDSize*8)
+	}
+
+	unit := int64(layout.Unit())
+	if (layout.Epoch*int64(time.Millisecond))%unit != 0 {
+		return nil, fmt.Errorf("%w: the epoch must be a whole # of %s intervals", ErrInvalidLayout, layout.Unit())
+	}
+
+	// ids are generated for the current time, which the time field must be
+	// able to represent
+	now := time.Now()
+	if layout.Epoch > now.UnixNano()/int64(time.Millisecond) {
+		return nil, fmt.Errorf("%w: the epoch (%d) is in the future", ErrInvalidLayout, layout.Epoch)
+	}
+
+	if _, err := layout.TimestampForTime(now); err != nil {
+		return nil, fmt.Errorf("%w: a %d-bit time field cannot represent the current time", ErrInvalidLayout, layout.TimeBits)
+	}
+
+	node, err := source.NodeValue(layout.NodeBits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &layoutSynthesizer{
+		layout: layout,
+		node:   node,
+		epoch:  layout.Epoch * int64(time.Millisecond) / unit,
+	}, nil
+}
+
+// NewLayoutGenerator creates an instance of generator (which implements
+// Generator) that generates identifiers with the fields described by layout
+// (see NewLayoutSynthesizer)
+func NewLayoutGenerator(layout Layout, source NodeSource, waitForTime int64, opts ...GeneratorOption) (Generator, error) {
+	idGen, err := NewLayoutSynthesizer(layout, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenerator(idGen, waitForTime, opts...), nil
+}
+
+// Node is the value of the node field of every identifier
+func (synth *layoutSynthesizer) Node() uint64 {
+	return synth.node
+}
+
+func (synth *layoutSynthesizer) IDSize() int {
+	return synth.layout.IDSize
+}
+
+func (synth *layoutSynthesizer) SequenceBitCount() uint64 {
+	return synth.layout.SequenceBits
+}
+
+func (synth *layoutSynthesizer) SequenceBitMask() uint64 {
+	return synth.layout.SequenceBitMask()
+}
+
+func (synth *layoutSynthesizer) MaxSequenceNumber() uint64 {
+	return synth.layout.SequenceBitMask()
+}
+
+func (synth *layoutSynthesizer) Epoch() int64 {
+	return synth.layout.Epoch
+}
+
+func (synth *layoutSynthesizer) Layout() Layout {
+	return synth.layout
+}
+
+// Decode implements IDDecoder
+func (synth *layoutSynthesizer) Decode(id []byte) (DecodedID, error) {
+	return synth.layout.Decode(id)
+}
+
+// SynthesizeID writes an id for time, which must be an interval the Layout can
+// represent. A Generator (or Backfiller) returns ErrTimeOutOfRange rather than
+// synthesizing an id for any other interval
+func (synth *layoutSynthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
+	layout := synth.layout
+
+	// time is the # of intervals since the Unix Epoch
+	hi, lo := insertBits(0, 0, layout.timeShift(), layout.TimeBits, uint64(time-synth.epoch))
+	hi, lo = insertBits(hi, lo, layout.sequenceShift(), layout.SequenceBits, sequence)
+	hi, lo = insertBits(hi, lo, layout.nodeShift(), layout.NodeBits, synth.node)
+
+	writeUint128(buffer[index:index+layout.IDSize], hi, lo)
+
+	return layout.IDSize
+}
@@ -0,0 +1,167 @@
+package flake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutGeneratorMatchesHandWrittenSynthesizers(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	twitter, err := NewLayoutGenerator(TwitterFlakeLayout, NewFixedNodeSource(7<<5|3), 0, WithClock(clock))
+	assert.NoError(t, err)
+
+	overtFlake, err := NewLayoutGenerator(DefaultOvertFlakeLayout, NewHardwareNodeSource(testHardwareID[0:6], 42), 0, WithClock(clock))
+	assert.NoError(t, err)
+
+	pairs := [][2]Generator{
+		{NewTwitterGenerator(3, 7, 0, WithClock(clock)), twitter},
+		{NewOvertFlakeGenerator(OvertoneEpochMs, testHardwareID, 42, 0, WithClock(clock)), overtFlake},
+	}
+
+	for _, pair := range pairs {
+		expected, err := pair[0].Generate(3)
+		assert.NoError(t, err)
+
+		ids, err := pair[1].Generate(3)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ids)
+	}
+}
+
+func TestLayoutGeneratorSizes(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	layouts := []Layout{
+		{IDSize: 8, Epoch: OvertoneEpochMs, TimeBits: 41, SequenceBits: 10, NodeBits: 12, Order: TimeNodeSequence},
+		{IDSize: 12, Epoch: OvertoneEpochMs, TimeBits: 44, SequenceBits: 20, NodeBits: 32},
+		{IDSize: 16, Epoch: UnixEpochMs, TimeBits: 54, SequenceBits: 10, NodeBits: 64, TimeUnit: time.Microsecond},
+		{IDSize: 8, Epoch: SonyflakeEpochMs, TimeBits: 39, SequenceBits: 8, NodeBits: 16, TimeUnit: 10 * time.Millisecond},
+	}
+
+	for _, layout := range layouts {
+		gen, err := NewLayoutGenerator(layout, NewFixedNodeSource(0xABC), 0, WithClock(clock))
+		if !assert.NoError(t, err, "layout %+v", layout) {
+			continue
+		}
+
+		assert.Equal(t, layout.IDSize, gen.IDSize())
+		assert.Equal(t, layout.SequenceBits, gen.SequenceBitCount())
+
+		ids, err := gen.Generate(3)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		for index, decoded := range decodeAll(t, gen, ids) {
+			assert.True(t, testStartTime.Equal(decoded.Time()), "layout %+v", layout)
+			assert.Equal(t, uint64(index), decoded.Sequence())
+			assert.Equal(t, uint64(0xABC), decoded.Node())
+		}
+	}
+}
+
+func TestLayoutGeneratorMicroseconds(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	layout := Layout{IDSize: 8, Epoch: OvertoneEpochMs, TimeBits: 50, SequenceBits: 4, NodeBits: 8, TimeUnit: time.Microsecond}
+	gen, err := NewLayoutGenerator(layout, NewProcessNodeSource(42), 0, WithClock(clock))
+	assert.NoError(t, err)
+
+	// 16 ids per microsecond
+	ids, err := gen.Generate(40)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), ids)
+	assert.Equal(t, testStartTime.Add(2*time.Microsecond), clock.Now())
+
+	decoded := decodeAll(t, gen, ids)
+	assert.True(t, testStartTime.Add(2*time.Microsecond).Equal(decoded[39].Time()))
+	assert.Equal(t, uint64(7), decoded[39].Sequence())
+}
+
+func TestLayoutGeneratorInvalidSpecs(t *testing.T) {
+	valid := Layout{IDSize: 8, Epoch: OvertoneEpochMs, TimeBits: 41, SequenceBits: 12, NodeBits: 10}
+	future := time.Now().Add(24*time.Hour).UnixNano() / int64(time.Millisecond)
+
+	invalid := []struct {
+		layout Layout
+		source NodeSource
+		err    error
+	}{
+		{Layout{IDSize: 10, TimeBits: 41, SequenceBits: 12, NodeBits: 10}, NewFixedNodeSource(1), ErrInvalidLayout},
+		{Layout{IDSize: 8, TimeBits: 42, SequenceBits: 12, NodeBits: 11}, NewFixedNodeSource(1), ErrInvalidLayout},
+		{Layout{IDSize: 8, Epoch: 5, TimeBits: 39, SequenceBits: 8, NodeBits: 16, TimeUnit: 10 * time.Millisecond}, NewFixedNodeSource(1), ErrInvalidLayout},
+		{Layout{IDSize: 16, TimeBits: 48, SequenceBits: 64}, NewFixedNodeSource(0), ErrInvalidLayout},
+		{Layout{IDSize: 8, Epoch: OvertoneEpochMs, TimeBits: 20, SequenceBits: 12, NodeBits: 10}, NewFixedNodeSource(1), ErrInvalidLayout},
+		{Layout{IDSize: 8, Epoch: future, TimeBits: 41, SequenceBits: 12, NodeBits: 10}, NewFixedNodeSource(1), ErrInvalidLayout},
+		{valid, NewFixedNodeSource(1024), ErrNodeOutOfRange},
+		{valid, NewProcessNodeSource(-1), ErrNodeOutOfRange},
+		{valid, NewHardwareNodeSource(testHardwareID[0:6], 42), ErrNodeOutOfRange},
+	}
+
+	for _, test := range invalid {
+		_, err := NewLayoutGenerator(test.layout, test.source, 0)
+		assert.True(t, errors.Is(err, test.err), "Expecting %v for %+v, got %v", test.err, test.layout, err)
+	}
+}
+
+func TestLayoutGeneratorTimeOutOfRange(t *testing.T) {
+	// an 11-bit time field covers the 2047ms after the epoch
+	now := time.Now().Truncate(time.Millisecond)
+	layout := Layout{IDSize: 8, Epoch: now.Add(-time.Second).UnixNano() / int64(time.Millisecond), TimeBits: 11, SequenceBits: 12, NodeBits: 10}
+
+	for _, lockFree := range []bool{false, true} {
+		clock := NewFakeClock(now)
+		opts := []GeneratorOption{WithClock(clock)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+
+		gen, err := NewLayoutGenerator(layout, NewFixedNodeSource(1), 0, opts...)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		_, err = gen.Generate(1)
+		assert.NoError(t, err)
+
+		// the time field would wrap rather than represent the time
+		clock.Advance(2 * time.Second)
+		_, err = gen.Generate(1)
+		assert.Equal(t, ErrTimeOutOfRange, err)
+
+		// before the epoch
+		clock.Set(now.Add(-2 * time.Second))
+		_, err = gen.Generate(1)
+		assert.Equal(t, ErrTimeOutOfRange, err)
+	}
+}
+
+func TestHardwareNodeSource(t *testing.T) {
+	source := NewHardwareNodeSource(HardwareID{0x11, 0x22}, 0x12345)
+
+	value, err := source.NodeValue(16)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x1122), value)
+
+	// the least-significant bits of the process id fill the rest of the field
+	value, err = source.NodeValue(24)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0x112245), value)
+
+	_, err = NewHardwareNodeSource(nil, 1).NodeValue(64)
+	assert.True(t, errors.Is(err, ErrNodeOutOfRange))
+}
+
+func TestParseFieldOrder(t *testing.T) {
+	for _, order := range []FieldOrder{TimeSequenceNode, TimeNodeSequence} {
+		parsed, err := ParseFieldOrder(order.String())
+		assert.NoError(t, err)
+		assert.Equal(t, order, parsed)
+	}
+
+	_, err := ParseFieldOrder("nodeTimeSequence")
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package flake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutDecodesNonDefaultSequenceBits(t *testing.T) {
+	generators := []Generator{
+		NewOvertFlakeGeneratorWithBits(OvertoneEpochMs, testHardwareID, 42, 0, 12),
+		NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 20),
+		NewOvertFlakeGenerator53(testHardwareID, 42, 0),
+		NewOvertoneEpochGenerator(testHardwareID),
+	}
+
+	for _, gen := range generators {
+		layout := gen.Layout()
+		assert.NoError(t, layout.Validate())
+		assert.Equal(t, gen.SequenceBitCount(), layout.SequenceBits)
+		assert.Equal(t, gen.Epoch(), layout.Epoch)
+
+		ids, err := gen.Generate(3)
+		assert.NoError(t, err)
+
+		expectedTimestamp := uint64(gen.LastAllocatedTime() - gen.Epoch())
+
+		for i := 0; i < 3; i++ {
+			decoded, err := gen.Decode(ids[i*OvertFlakeIDLength : (i+1)*OvertFlakeIDLength])
+			assert.NoError(t, err)
+
+			assert.Equal(t, expectedTimestamp, decoded.Timestamp(), "layout %+v", layout)
+			assert.Equal(t, uint64(i), decoded.Sequence(), "layout %+v", layout)
+
+			// overt-flake layouts decode to an OvertFlakeID
+			ofid, ok := decoded.(OvertFlakeID)
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, uint16(i), ofid.SequenceID())
+				assert.Equal(t, uint16(gen.IDGenerator().(OvertFlakeIDGenerator).ProcessID()), ofid.ProcessID())
+				assert.Equal(t, ofid.MachineID(), decoded.Node())
+			}
+		}
+	}
+}
+
+func TestLayoutDecodesTwitterFlakeIDs(t *testing.T) {
+	gen := NewTwitterGenerator(3, 7, 0)
+
+	ids, err := gen.Generate(2)
+	assert.NoError(t, err)
+
+	decoded, err := gen.Decode(ids[8:16])
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(gen.LastAllocatedTime()-SnowflakeEpochMs), decoded.Timestamp())
+	assert.Equal(t, uint64(1), decoded.Sequence())
+	assert.Equal(t, uint64(7<<5|3), decoded.Node())
+
+	_, err = gen.Decode(ids)
+	assert.Equal(t, ErrInvalidIDLength, err)
+}
+
+func TestLayoutValidate(t *testing.T) {
+	invalid := []Layout{
+		{IDSize: 0, TimeBits: 41},
+		{IDSize: 17, TimeBits: 41},
+		{IDSize: 8, TimeBits: 0},
+		{IDSize: 8, TimeBits: 41, SequenceBits: 12, NodeBits: 12},
+		{IDSize: 16, TimeBits: 48, SequenceBits: 16, NodeBits: 65},
+		{IDSize: 8, TimeBits: 41, Order: FieldOrder(7)},
+		{IDSize: 8, TimeBits: 41, TimeUnit: 7 * time.Millisecond},
+		{IDSize: 8, TimeBits: 41, TimeUnit: time.Minute},
+	}
+
+	for _, layout := range invalid {
+		err := layout.Validate()
+		assert.True(t, errors.Is(err, ErrInvalidLayout), "Expecting %+v to be invalid", layout)
+	}
+
+	for _, layout := range []Layout{DefaultOvertFlakeLayout, OvertFlake53Layout, TwitterFlakeLayout} {
+		assert.NoError(t, layout.Validate())
+	}
+}
+
+func TestInsertAndExtractBits(t *testing.T) {
+	fields := []struct{ shift, width, value uint64 }{
+		{0, 16, 0xBEEF},
+		{60, 8, 0xA5}, // straddles the 64-bit boundary
+		{68, 52, 0xFFFFFFFFFFFFF},
+		{64, 64, 0x0123456789ABCDEF},
+	}
+
+	for _, field := range fields {
+		hi, lo := insertBits(0, 0, field.shift, field.width, field.value)
+		assert.Equal(t, field.value, extractBits(hi, lo, field.shift, field.width))
+
+		b := make([]byte, 16)
+		writeUint128(b, hi, lo)
+		rhi, rlo := readUint128(b)
+		assert.Equal(t, hi, rhi)
+		assert.Equal(t, lo, rlo)
+	}
+}
+
+func TestLayoutTimeRange(t *testing.T) {
+	layouts := []Layout{DefaultOvertFlakeLayout, OvertFlake53Layout, TwitterFlakeLayout}
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Second)
+
+	for _, layout := range layouts {
+		min, err := layout.MinIDForTime(start)
+		assert.NoError(t, err)
+		max, err := layout.MaxIDForTime(end)
+		assert.NoError(t, err)
+
+		assert.Equal(t, layout.IDSize, len(min))
+		assert.Equal(t, layout.IDSize, len(max))
+
+		assert.True(t, start.Equal(layout.Time(min)), "layout %+v", layout)
+		assert.True(t, end.Equal(layout.Time(max)), "layout %+v", layout)
+		assert.Equal(t, uint64(0), layout.Sequence(min))
+		assert.Equal(t, uint64(0), layout.Node(min))
+		assert.Equal(t, layout.SequenceBitMask(), layout.Sequence(max))
+		assert.Equal(t, bitMask(layout.NodeBits), layout.Node(max))
+
+		// an id generated inside the range sorts between the boundaries, and one
+		// generated 1ms after the range sorts after max
+		inside, err := layout.MaxIDForTime(start.Add(500 * time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, -1, bytes.Compare(min, inside))
+		assert.Equal(t, -1, bytes.Compare(inside, max))
+
+		after, err := layout.MinIDForTime(end.Add(time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, bytes.Compare(after, max))
+
+		// the unused most-significant bits remain 0
+		_, err = layout.MaxIDForTime(layout.TimeForTimestamp(bitMask(layout.TimeBits)))
+		assert.NoError(t, err)
+		_, err = layout.MaxIDForTime(layout.TimeForTimestamp(bitMask(layout.TimeBits)).Add(time.Millisecond))
+		assert.Equal(t, ErrTimeOutOfRange, err)
+
+		_, err = layout.MinIDForTime(layout.TimeForTimestamp(0).Add(-time.Nanosecond))
+		assert.Equal(t, ErrTimeOutOfRange, err)
+	}
+
+	// snowflakes in the range must be positive int64 values
+	max, err := TwitterFlakeLayout.MaxIDForTime(end)
+	assert.NoError(t, err)
+	assert.True(t, NewTwitterFlakeID(max).Int64() > 0)
+}
+
+func TestLayoutTimeUnits(t *testing.T) {
+	at := time.Date(2020, 6, 1, 12, 0, 0, 123456789, time.UTC)
+
+	units := []struct {
+		unit      time.Duration
+		timestamp uint64
+		truncated time.Time
+	}{
+		{0, uint64(at.UnixNano() / int64(time.Millisecond)), at.Truncate(time.Millisecond)},
+		{time.Microsecond, uint64(at.UnixNano() / int64(time.Microsecond)), at.Truncate(time.Microsecond)},
+		{10 * time.Millisecond, uint64(at.UnixNano() / int64(10*time.Millisecond)), at.Truncate(10 * time.Millisecond)},
+	}
+
+	for _, test := range units {
+		layout := Layout{IDSize: 8, TimeBits: 52, SequenceBits: 12, TimeUnit: test.unit}
+		assert.NoError(t, layout.Validate())
+
+		timestamp, err := layout.TimestampForTime(at)
+		assert.NoError(t, err)
+		assert.Equal(t, test.timestamp, timestamp, "unit %s", test.unit)
+		assert.True(t, test.truncated.Equal(layout.TimeForTimestamp(timestamp)), "unit %s", test.unit)
+	}
+
+	// the epoch is applied in milliseconds, whatever the unit
+	timestamp, err := SonyflakeLayout.TimestampForTime(at)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(at.Sub(time.Unix(SonyflakeEpochMs/1000, 0))/(10*time.Millisecond)), timestamp)
+	assert.True(t, at.Truncate(10*time.Millisecond).Equal(SonyflakeLayout.TimeForTimestamp(timestamp)))
+
+	// the 39 bits of a sonyflake last for 174 years
+	_, err = SonyflakeLayout.TimestampForTime(time.Unix(SonyflakeEpochMs/1000, 0).AddDate(174, 0, 0))
+	assert.NoError(t, err)
+	_, err = SonyflakeLayout.TimestampForTime(time.Unix(SonyflakeEpochMs/1000, 0).AddDate(175, 0, 0))
+	assert.Equal(t, ErrTimeOutOfRange, err)
+}
+
+func TestDecodedIDTime(t *testing.T) {
+	gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 12)
+	before := time.Now().Truncate(time.Millisecond)
+
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	decoded, err := gen.Decode(ids)
+	assert.NoError(t, err)
+
+	assert.False(t, decoded.Time().Before(before))
+	assert.False(t, decoded.Time().After(time.Now()))
+	assert.Equal(t, gen.LastAllocatedTime(), decoded.Time().UnixNano()/int64(time.Millisecond))
+
+	id := IDFromOvertFlakeID(MustParseOvertFlakeID("0x00000000000100000000000000000000"))
+	assert.True(t, time.Unix(0, OvertoneEpochMs*int64(time.Millisecond)).Add(time.Millisecond).Equal(id.Time()))
+}
@@ -0,0 +1,151 @@
+package flake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeGeneratorState(t *testing.T) {
+	gen := NewLockFreeGenerator(NewOvertFlakeIDSynthesizer(UnixEpochMs, 4, testHardwareID, 42), 0).(*generator)
+
+	for _, lastTime := range []int64{0, 1, testStartTime.UnixNano() / int64(time.Millisecond)} {
+		for _, sequence := range []uint64{1, 7, 16} {
+			unpackedTime, unpackedSequence := gen.unpackState(gen.packState(lastTime, sequence))
+			assert.Equal(t, lastTime, unpackedTime)
+			assert.Equal(t, sequence, unpackedSequence)
+		}
+	}
+}
+
+func TestLockFreeGenerator(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	waitForTime := testStartTime.UnixNano() / int64(time.Millisecond)
+	gen := NewLockFreeGenerator(NewOvertFlakeIDSynthesizer(UnixEpochMs, 2, testHardwareID, 42), waitForTime,
+		WithClock(clock))
+	assert.Equal(t, waitForTime-1, gen.LastAllocatedTime())
+
+	// the waitForTime interval is the first interval used
+	ids, err := gen.Generate(6)
+	assert.NoError(t, err)
+
+	for index, id := range decodeAll(t, gen, ids) {
+		assert.True(t, testStartTime.Add(time.Duration(index/4)*time.Millisecond).Equal(id.Time()))
+		assert.Equal(t, uint64(index%4), id.Sequence())
+	}
+	assert.Equal(t, waitForTime+1, gen.LastAllocatedTime())
+
+	// a clock regression is detected
+	clock.Advance(-time.Millisecond)
+	_, err = gen.Generate(1)
+	assert.Equal(t, ErrTimeIsMovingBackwards, err)
+}
+
+func TestLockFreeGeneratorClockRegressionReuse(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewLockFreeGenerator(NewOvertFlakeIDSynthesizer(UnixEpochMs, 2, testHardwareID, 42), 0,
+		WithClock(clock), WithClockRegressionPolicy(ClockRegressionReuse, 10*time.Millisecond))
+
+	_, err := gen.Generate(1)
+	assert.NoError(t, err)
+	last := gen.LastAllocatedTime()
+	clock.Advance(-5 * time.Millisecond)
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	for index, id := range decodeAll(t, gen, ids) {
+		assert.Equal(t, uint64(last), id.Timestamp())
+		assert.Equal(t, uint64(index+1), id.Sequence())
+	}
+}
+
+func TestLockFreeGeneratorEpoch(t *testing.T) {
+	// 24 sequence bits leaves 40 bits for the interval, which only fits the
+	// time since an epoch
+	idGen := NewOvertFlakeIDSynthesizer(OvertoneEpochMs, 24, testHardwareID, 42)
+	assert.True(t, SupportsLockFree(idGen))
+
+	gen := NewLockFreeGenerator(idGen, 0, WithClock(NewFakeClock(testStartTime)))
+	assert.True(t, gen.(*generator).lockFree)
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+
+	for index, id := range decodeAll(t, gen, ids) {
+		assert.True(t, testStartTime.Equal(id.Time()))
+		assert.Equal(t, uint64(index), id.Sequence())
+	}
+	assert.Equal(t, testStartTime.UnixNano()/int64(time.Millisecond), gen.LastAllocatedTime())
+}
+
+func TestLockFreeGeneratorWideLayouts(t *testing.T) {
+	// 48 time bits and 30 sequence bits do not fit in the packed state, so the
+	// generator falls back to the mutex
+	wide := Layout{IDSize: 16, Epoch: UnixEpochMs, TimeBits: 48, SequenceBits: 30, NodeBits: 16, Order: TimeSequenceNode}
+
+	// 54 time bits (microseconds) and 10 sequence bits fit exactly
+	micro := Layout{IDSize: 8, Epoch: OvertoneEpochMs, TimeBits: 54, SequenceBits: 10, Order: TimeSequenceNode, TimeUnit: time.Microsecond}
+
+	for _, layout := range []Layout{wide, micro} {
+		idGen, err := NewLayoutSynthesizer(layout, NewFixedNodeSource(0))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, layout == micro, SupportsLockFree(idGen))
+
+		gen := NewLockFreeGenerator(idGen, 0, WithClock(NewFakeClock(testStartTime)))
+		assert.Equal(t, layout == micro, gen.(*generator).lockFree)
+
+		ids, err := gen.Generate(3000)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		decoded := decodeAll(t, gen, ids)
+		assert.True(t, testStartTime.Equal(decoded[0].Time()))
+		assert.Equal(t, decoded[len(decoded)-1].Time().UnixNano()/int64(time.Millisecond), gen.LastAllocatedTime())
+	}
+}
+
+func TestLockFreeGeneratorConcurrency(t *testing.T) {
+	const workers = 8
+	const requests = 200
+
+	// 4 sequence bits ensures plenty of contention for each interval
+	gen := NewLockFreeGenerator(NewOvertFlakeIDSynthesizer(UnixEpochMs, 4, testHardwareID, 42), 0)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, workers)
+
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for request := 0; request < requests; request++ {
+				ids, err := gen.Generate(3)
+				if !assert.NoError(t, err) {
+					return
+				}
+				results[worker] = append(results[worker], ids...)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool)
+	for _, ids := range results {
+		// each caller sees strictly increasing ids
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		for index := 0; index < len(ids); index += gen.IDSize() {
+			id, err := IDFromBytes(ids[index : index+gen.IDSize()])
+			assert.NoError(t, err)
+			assert.False(t, seen[id], "duplicate id %s", id)
+			seen[id] = true
+		}
+	}
+
+	assert.Equal(t, workers*requests*3, len(seen))
+}
@@ -0,0 +1,104 @@
+package flake
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+)
+
+// JSONFormat determines the string representation used when identifiers are
+// marshaled to (and unmarshaled from) JSON. Identifiers are always represented
+// as JSON strings because 128-bit values cannot be represented accurately by
+// JSON numbers in most languages
+type JSONFormat int
+
+const (
+	// JSONDecimal represents identifiers using their decimal form (the default)
+	JSONDecimal JSONFormat = iota
+	// JSONHex represents identifiers using 32 lower case hexadecimal digits
+	JSONHex
+	// JSONBase62 represents identifiers using 22 base62 digits
+	JSONBase62
+)
+
+// JSONIDFormat is the JSONFormat used by the json.Marshaler and
+// json.Unmarshaler implementations of identifiers in this package.
+//
+// It is intended to be set once during program initialization
+var JSONIDFormat = JSONDecimal
+
+// String returns the name of the format
+func (format JSONFormat) String() string {
+	switch format {
+	case JSONDecimal:
+		return "decimal"
+	case JSONHex:
+		return "hex"
+	case JSONBase62:
+		return "base62"
+	}
+
+	return "unknown"
+}
+
+// encodeID returns the string representation of the 128-bit id for format
+func (format JSONFormat) encodeID(id []byte) string {
+	switch format {
+	case JSONHex:
+		return encodeHex(id)
+	case JSONBase62:
+		return base62Encoding.encode(id)
+	}
+
+	return new(big.Int).SetBytes(id).String()
+}
+
+// decodeID parses s, the string representation of a 128-bit id for format,
+// into dst
+func (format JSONFormat) decodeID(s string, dst []byte) error {
+	switch format {
+	case JSONHex:
+		return decodeHex(s, dst)
+	case JSONBase62:
+		return base62Encoding.decode(s, dst)
+	}
+
+	return decodeDigits(s, 10, isDecimalDigit, dst)
+}
+
+// marshalJSONID marshals a 128-bit id as a JSON string using JSONIDFormat
+func marshalJSONID(id []byte) ([]byte, error) {
+	return json.Marshal(JSONIDFormat.encodeID(id))
+}
+
+// isJSONNull determines if data is the JSON literal null. By convention,
+// json.Unmarshaler implementations treat null as a no-op
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// unmarshalJSONID unmarshals a JSON string produced by marshalJSONID into dst
+func unmarshalJSONID(data []byte, dst []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalidJSONID
+	}
+
+	if err := JSONIDFormat.decodeID(s, dst); err != nil {
+		return &ParseError{Input: s, Err: err}
+	}
+
+	return nil
+}
+
+// unmarshalTextID parses the decimal (or 0x prefixed hexadecimal) text form of
+// a 128-bit id into dst
+func unmarshalTextID(text []byte, dst []byte) error {
+	id, err := ParseOvertFlakeID(string(text))
+	if err != nil {
+		return err
+	}
+
+	copy(dst, id.Bytes())
+	return nil
+}
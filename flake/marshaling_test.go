@@ -0,0 +1,91 @@
+package flake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	ID   OvertFlakeID `json:"id"`
+	Name string       `json:"name"`
+}
+
+func TestOvertFlakeIDJSON(t *testing.T) {
+	defer func(format JSONFormat) { JSONIDFormat = format }(JSONIDFormat)
+
+	id := MustParseOvertFlakeID("0x0000000000000001000000000000002A")
+
+	expected := map[JSONFormat]string{
+		JSONDecimal: `{"id":"18446744073709551658","name":"test"}`,
+		JSONHex:     `{"id":"0000000000000001000000000000002a","name":"test"}`,
+		JSONBase62:  `{"id":"00000000000LygHa16AHYw","name":"test"}`,
+	}
+
+	for format, js := range expected {
+		JSONIDFormat = format
+
+		data, err := json.Marshal(testPayload{ID: id, Name: "test"})
+		assert.NoError(t, err, format.String())
+		assert.Equal(t, js, string(data), format.String())
+
+		payload := testPayload{ID: NewOvertFlakeID(nil)}
+		err = json.Unmarshal(data, &payload)
+		assert.NoError(t, err, format.String())
+		assert.Equal(t, id.Bytes(), payload.ID.Bytes(), format.String())
+	}
+}
+
+func TestOvertFlakeIDJSONErrors(t *testing.T) {
+	defer func(format JSONFormat) { JSONIDFormat = format }(JSONIDFormat)
+	JSONIDFormat = JSONDecimal
+
+	id := NewOvertFlakeID(nil)
+
+	err := json.Unmarshal([]byte(`18446744073709551658`), id)
+	assert.True(t, errors.Is(err, ErrInvalidJSONID), "Expecting ErrInvalidJSONID, not %v", err)
+
+	err = json.Unmarshal([]byte(`"0x2A"`), id)
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax), "Expecting ErrInvalidIDSyntax, not %v", err)
+
+	// null is a no-op
+	id = MustParseOvertFlakeID("42")
+	assert.NoError(t, json.Unmarshal([]byte(`null`), id))
+	assert.Equal(t, "42", id.String())
+}
+
+func TestOvertFlakeIDTextAndBinary(t *testing.T) {
+	id := MustParseOvertFlakeID("0x0000000000000001000000000000002A")
+
+	text, err := id.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "18446744073709551658", string(text))
+
+	parsed := NewOvertFlakeID(nil)
+	assert.NoError(t, parsed.UnmarshalText(text))
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+
+	assert.NoError(t, parsed.UnmarshalText([]byte("0x10000000000000000")))
+	assert.Equal(t, uint64(1), parsed.Upper())
+
+	binary, err := id.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), binary)
+
+	// the marshaled bytes must not alias the ID
+	binary[0] = 0xFF
+	assert.Equal(t, uint64(1), id.Upper())
+
+	parsed = NewOvertFlakeID(nil)
+	assert.NoError(t, parsed.UnmarshalBinary(id.Bytes()))
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+
+	assert.Equal(t, ErrInvalidIDLength, parsed.UnmarshalBinary(make([]byte, 8)))
+
+	// as map keys, IDs use their text form
+	data, err := json.Marshal(map[*overtFlakeID]int{id.(*overtFlakeID): 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"18446744073709551658":1}`, string(data))
+}
@@ -0,0 +1,181 @@
+package flake
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NodeRegistry records the node values in use by generators whose node is a
+// hash (see HashedNodeIDGenerator), so that a collision can be detected before
+// any ids are generated. Each node is recorded with its owner (see NodeOwner),
+// and an owner has at most 1 node
+type NodeRegistry map[uint64]string
+
+// NodeOwner is the owner of the node of a generator in a NodeRegistry, which
+// identifies the generator by its hardware id and process id, so that the
+// generators of different processes on 1 host are distinct owners
+func NodeOwner(hardwareID HardwareID, processID int) string {
+	return fmt.Sprintf("%x:%d", []byte(hardwareID), processID)
+}
+
+// ReadNodeRegistry reads the registry file at path. Each line of the file is a
+// node value followed by its owner, and blank lines and lines starting with #
+// are ignored. If the file does not exist then an empty registry is returned
+func ReadNodeRegistry(path string) (NodeRegistry, error) {
+	registry := NodeRegistry{}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registry, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: %s:%d", ErrInvalidNodeRegistry, path, line)
+		}
+
+		node, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s:%d", ErrInvalidNodeRegistry, path, line)
+		}
+
+		registry[node] = fields[1]
+	}
+
+	return registry, nil
+}
+
+// WriteNodeRegistry durably replaces the registry file at path with registry
+func WriteNodeRegistry(path string, registry NodeRegistry) error {
+	nodes := make([]uint64, 0, len(registry))
+	for node := range registry {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	var buffer bytes.Buffer
+	buffer.WriteString("# node owner\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&buffer, "%d %s\n", node, registry[node])
+	}
+
+	return writeFileAtomic(path, buffer.Bytes())
+}
+
+// Register records node for owner, replacing any node previously recorded for
+// owner (ie. by an earlier process with the same process id). An error
+// wrapping ErrNodeCollision is returned (and the registry is unchanged) if
+// node is recorded for a different owner, unless that owner is a process on
+// the same host (hardware id) as owner that is no longer running (ie. it was
+// killed before it could unregister its node)
+func (registry NodeRegistry) Register(node uint64, owner string) error {
+	if other, ok := registry[node]; ok && other != owner && !isDeadOwner(other, owner) {
+		return fmt.Errorf("%w: node %d is registered to %s", ErrNodeCollision, node, other)
+	}
+
+	for recorded, recordedOwner := range registry {
+		if recordedOwner == owner {
+			delete(registry, recorded)
+		}
+	}
+
+	registry[node] = owner
+
+	return nil
+}
+
+// Unregister removes node from the registry, if it is recorded for owner
+func (registry NodeRegistry) Unregister(node uint64, owner string) {
+	if registry[node] == owner {
+		delete(registry, node)
+	}
+}
+
+// isDeadOwner reports whether other is the owner of a process on the same
+// host as owner that is no longer running. The processes of other hosts
+// cannot be checked, so they are never considered dead
+func isDeadOwner(other, owner string) bool {
+	otherHost, otherProcessID, ok := parseNodeOwner(other)
+	if !ok {
+		return false
+	}
+
+	host, _, ok := parseNodeOwner(owner)
+	if !ok || host != otherHost {
+		return false
+	}
+
+	return !processAlive(otherProcessID)
+}
+
+// parseNodeOwner splits owner (see NodeOwner) into its hardware id (in hex)
+// and process id
+func parseNodeOwner(owner string) (string, int, bool) {
+	separator := strings.LastIndex(owner, ":")
+	if separator < 0 {
+		return "", 0, false
+	}
+
+	processID, err := strconv.Atoi(owner[separator+1:])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return owner[:separator], processID, true
+}
+
+// RegisterNode registers node for owner in the registry file at path (see
+// NodeRegistry.Register), creating the file if it does not exist. The file is
+// locked while it is updated, so that generators starting at the same time
+// detect a collision between them
+func RegisterNode(path string, node uint64, owner string) error {
+	return updateNodeRegistry(path, func(registry NodeRegistry) error {
+		return registry.Register(node, owner)
+	})
+}
+
+// UnregisterNode removes node, if it is recorded for owner, from the registry
+// file at path (ie. when the generator stops)
+func UnregisterNode(path string, node uint64, owner string) error {
+	return updateNodeRegistry(path, func(registry NodeRegistry) error {
+		registry.Unregister(node, owner)
+		return nil
+	})
+}
+
+// updateNodeRegistry applies update to the registry file at path while holding
+// a lock on path + ".lock" (the registry file itself is replaced rather than
+// written)
+func updateNodeRegistry(path string, update func(NodeRegistry) error) error {
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	registry, err := ReadNodeRegistry(path)
+	if err != nil {
+		return err
+	}
+
+	if err = update(registry); err != nil {
+		return err
+	}
+
+	return WriteNodeRegistry(path, registry)
+}
@@ -0,0 +1,123 @@
+package flake
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+
+	// a missing registry is empty
+	registry, err := ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Empty(t, registry)
+
+	hostA := NodeOwner(HardwareID{0xa}, 42)
+	hostB := NodeOwner(HardwareID{0xb}, 42)
+	assert.Equal(t, "0a:42", hostA)
+
+	assert.NoError(t, RegisterNode(path, 10, hostA))
+	assert.NoError(t, RegisterNode(path, 20, hostB))
+
+	// re-registering an owner replaces its node (ie. a restart that reuses
+	// the process id)
+	assert.NoError(t, RegisterNode(path, 11, hostA))
+	assert.NoError(t, RegisterNode(path, 11, hostA))
+
+	err = RegisterNode(path, 20, NodeOwner(HardwareID{0xc}, 42))
+	assert.True(t, errors.Is(err, ErrNodeCollision))
+
+	registry, err = ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeRegistry{11: hostA, 20: hostB}, registry)
+
+	// only the owner can unregister its node
+	assert.NoError(t, UnregisterNode(path, 20, hostA))
+	assert.NoError(t, UnregisterNode(path, 11, hostA))
+
+	registry, err = ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeRegistry{20: hostB}, registry)
+}
+
+func TestNodeRegistrySameHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+
+	// 2 (running) processes on the same host are distinct owners, so neither
+	// evicts the node of the other
+	first := NodeOwner(testHardwareID, os.Getpid())
+	second := NodeOwner(testHardwareID, os.Getppid())
+
+	assert.NoError(t, RegisterNode(path, 10, first))
+	assert.NoError(t, RegisterNode(path, 20, second))
+
+	// and a collision between them is detected
+	err := RegisterNode(path, 10, NodeOwner(testHardwareID, 300))
+	assert.True(t, errors.Is(err, ErrNodeCollision))
+
+	registry, err := ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeRegistry{10: first, 20: second}, registry)
+}
+
+func TestNodeRegistryConcurrentRegistration(t *testing.T) {
+	const processes = 20
+
+	path := filepath.Join(t.TempDir(), "nodes")
+
+	var wg sync.WaitGroup
+	collisions := make(chan error, processes)
+
+	// every process registers a distinct node, and also the same node
+	for processID := 0; processID < processes; processID++ {
+		wg.Add(1)
+		go func(processID int) {
+			defer wg.Done()
+
+			// each process is on its own host, as the processes do not exist
+			hardwareID := HardwareID{byte(processID)}
+
+			owner := NodeOwner(hardwareID, 1)
+			assert.NoError(t, RegisterNode(path, uint64(processID), owner))
+
+			if err := RegisterNode(path, 1000, NodeOwner(hardwareID, 2)); err != nil {
+				collisions <- err
+			}
+		}(processID)
+	}
+	wg.Wait()
+	close(collisions)
+
+	// none of the updates were lost, and only 1 process registered node 1000
+	registry, err := ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Len(t, registry, processes+1)
+
+	var collided int
+	for err := range collisions {
+		assert.True(t, errors.Is(err, ErrNodeCollision))
+		collided++
+	}
+	assert.Equal(t, processes-1, collided)
+}
+
+func TestNodeRegistryInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("# comment\n\n10 host-a\n11\n"), 0644))
+	_, err := ReadNodeRegistry(path)
+	assert.True(t, errors.Is(err, ErrInvalidNodeRegistry))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("x host-a\n"), 0644))
+	_, err = ReadNodeRegistry(path)
+	assert.True(t, errors.Is(err, ErrInvalidNodeRegistry))
+
+	assert.Error(t, RegisterNode(path, 1, NodeOwner(testHardwareID, 42)))
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package flake
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// deadProcessID is larger than the process ids of any supported platform
+const deadProcessID = 1 << 30
+
+func TestNodeRegistryDeadOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+
+	// a process that was killed before it could unregister its node
+	dead := NodeOwner(testHardwareID, deadProcessID)
+	assert.NoError(t, RegisterNode(path, 10, dead))
+	assert.NoError(t, RegisterNode(path, 20, NodeOwner(HardwareID{0xb}, deadProcessID)))
+
+	// does not block a process on the same host
+	owner := NodeOwner(testHardwareID, os.Getpid())
+	assert.NoError(t, RegisterNode(path, 10, owner))
+
+	// but a process on another host cannot be checked
+	err := RegisterNode(path, 20, owner)
+	assert.True(t, errors.Is(err, ErrNodeCollision))
+
+	registry, err := ReadNodeRegistry(path)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeRegistry{10: owner, 20: NodeOwner(HardwareID{0xb}, deadProcessID)}, registry)
+}
@@ -40,6 +40,7 @@ const (
 //  ---------------------------------------------------------------------------
 
 type overtFlakeIDSynthesizer struct {
+	layout       Layout
 	epoch        int64
 	sequenceBits uint64
 	sequenceMask uint64
@@ -62,6 +63,7 @@ func NewOvertFlakeIDSynthesizer(epoch int64, sequenceBits uint64, hardwareID Har
 	copy(tempBytes[0:6], hardwareID[0:6])
 
 	return &overtFlakeIDSynthesizer{
+		layout:       NewOvertFlakeLayout(epoch, sequenceBits),
 		epoch:        epoch,
 		sequenceBits: sequenceBits,
 		sequenceMask: uint64(int64(-1) ^ (int64(-1) << sequenceBits)),
@@ -78,26 +80,18 @@ func NewOvertFlakeIDSynthesizer(epoch int64, sequenceBits uint64, hardwareID Har
 // Notes
 //
 // Setting a value of seqBits > 22 will result in unacceptable time truncation
-func NewOvertFlakeGeneratorWithBits(epoch int64, hardwareID HardwareID, processID int, waitForTime int64, seqBits uint64) Generator {
-	// binary.BigEndian.Uint64 won't work on a []byte < len(8) so we need to
-	// copy our 6-byte hardwareID into the most-signficant bits
-	tempBytes := make([]byte, 8)
-	copy(tempBytes[0:6], hardwareID[0:6])
-
-	return &generator{
-		idGen:    NewOvertFlakeIDSynthesizer(epoch, seqBits, hardwareID, processID),
-		lastTime: waitForTime,
-	}
+func NewOvertFlakeGeneratorWithBits(epoch int64, hardwareID HardwareID, processID int, waitForTime int64, seqBits uint64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewOvertFlakeIDSynthesizer(epoch, seqBits, hardwareID, processID), waitForTime, opts...)
 }
 
 // NewOvertFlakeGenerator creates an instance of generator which implements Generator
-func NewOvertFlakeGenerator(epoch int64, hardwareID HardwareID, processID int, waitForTime int64) Generator {
-	return NewOvertFlakeGeneratorWithBits(epoch, hardwareID, processID, waitForTime, DefaultSequenceBits)
+func NewOvertFlakeGenerator(epoch int64, hardwareID HardwareID, processID int, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewOvertFlakeGeneratorWithBits(epoch, hardwareID, processID, waitForTime, DefaultSequenceBits, opts...)
 }
 
 // NewOvertoneEpochGenerator creates an instance of generator using the Overtone Epoch
-func NewOvertoneEpochGenerator(hardwareID HardwareID) Generator {
-	return NewOvertFlakeGeneratorWithBits(OvertoneEpochMs, hardwareID, os.Getpid(), 0, DefaultSequenceBits)
+func NewOvertoneEpochGenerator(hardwareID HardwareID, opts ...GeneratorOption) Generator {
+	return NewOvertFlakeGeneratorWithBits(OvertoneEpochMs, hardwareID, os.Getpid(), 0, DefaultSequenceBits, opts...)
 }
 
 func (ofid *overtFlakeIDSynthesizer) HardwareID() HardwareID {
@@ -128,6 +122,10 @@ func (ofid *overtFlakeIDSynthesizer) Epoch() int64 {
 	return ofid.epoch
 }
 
+func (ofid *overtFlakeIDSynthesizer) Layout() Layout {
+	return ofid.layout
+}
+
 func (ofid *overtFlakeIDSynthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
 	// time is Unix Epoch (note that this is inefficient in that delta has to be calculated for
 	// each id, when the generator could do the calculation + shift 1 time per allocate)
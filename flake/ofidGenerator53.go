@@ -52,6 +52,7 @@ func NewOvertFlakeID53Synthesizer(hardwareID HardwareID, processID int) OvertFla
 	copy(tempBytes[0:6], hardwareID[0:6])
 
 	return &overtFlakeIDSynthesizer{
+		layout:       OvertFlake53Layout,
 		epoch:        OvertoneEpochMs,
 		sequenceBits: SequenceBits53,
 		sequenceMask: uint64(int64(-1) ^ (int64(-1) << SequenceBits53)),
@@ -63,14 +64,6 @@ func NewOvertFlakeID53Synthesizer(hardwareID HardwareID, processID int) OvertFla
 }
 
 // NewOvertFlakeGenerator53 creates an instance of generator which implements Generator
-func NewOvertFlakeGenerator53(hardwareID HardwareID, processID int, waitForTime int64) Generator {
-	// binary.BigEndian.Uint64 won't work on a []byte < len(8) so we need to
-	// copy our 6-byte hardwareID into the most-signficant bits
-	tempBytes := make([]byte, 8)
-	copy(tempBytes[0:6], hardwareID[0:6])
-
-	return &generator{
-		idGen:    NewOvertFlakeID53Synthesizer(hardwareID, processID),
-		lastTime: waitForTime,
-	}
+func NewOvertFlakeGenerator53(hardwareID HardwareID, processID int, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewOvertFlakeID53Synthesizer(hardwareID, processID), waitForTime, opts...)
 }
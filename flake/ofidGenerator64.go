@@ -0,0 +1,136 @@
+package flake
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+//  ---------------------------------------------------------------------------
+//  Layout - Big Endian (64-bit overt-flake)
+//  ---------------------------------------------------------------------------
+//
+//  [0:8]    1 bit  | unused (0, so ids are positive as int64)
+//          41 bits | timestamp (milliseconds since the epoch)
+//           8 bits | sequence #
+//          14 bits | node (hash of the hardware id and process id)
+//
+//  ---------------------------------------------------------------------------
+//  Notes
+//  ---------------------------------------------------------------------------
+//  Like the 128-bit overt-flake id no node configuration is required, but the
+//  node is a 14-bit hash rather than the hardware id and process id, so
+//  generators may collide. The probability of a collision within a fleet is
+//  reported by NodeCollisionProbability, and NodeRegistry can be used to
+//  detect collisions between generators that share a registry file
+//  ---------------------------------------------------------------------------
+
+const (
+	// OvertFlakeID64Length is the length, in bytes, of a 64-bit overt-flake id
+	OvertFlakeID64Length = 8
+	// SequenceBits64 is the # of sequence bits of a 64-bit overt-flake id
+	SequenceBits64 uint64 = 8
+	// NodeBits64 is the # of node bits of a 64-bit overt-flake id
+	NodeBits64 uint64 = 14
+)
+
+// OvertFlake64Layout is the Layout of the 64-bit overt-flake variant (see
+// NewOvertFlakeGenerator64)
+var OvertFlake64Layout = Layout{
+	IDSize:       OvertFlakeID64Length,
+	Epoch:        OvertoneEpochMs,
+	TimeBits:     41,
+	SequenceBits: SequenceBits64,
+	NodeBits:     NodeBits64,
+	Order:        TimeSequenceNode,
+}
+
+type hashedNodeSource struct {
+	hardwareID HardwareID
+	processID  int
+}
+
+// NewHashedNodeSource creates a NodeSource whose value is the leading bits of
+// the SHA-256 hash of hardwareID followed by the (32-bit, big endian) process
+// ID. Any size of node field can be filled, at the expense of a chance of
+// collision (see NodeCollisionProbability)
+func NewHashedNodeSource(hardwareID HardwareID, processID int) NodeSource {
+	return &hashedNodeSource{
+		hardwareID: hardwareID,
+		processID:  processID,
+	}
+}
+
+func (hashed *hashedNodeSource) NodeValue(bits uint64) (uint64, error) {
+	var pid [4]byte
+	binary.BigEndian.PutUint32(pid[:], uint32(hashed.processID))
+
+	hash := sha256.Sum256(append(append([]byte{}, hashed.hardwareID...), pid[:]...))
+	if bits == 0 {
+		return 0, nil
+	}
+
+	return binary.BigEndian.Uint64(hash[0:8]) >> (64 - bits), nil
+}
+
+// NodeCollisionProbability is the probability that at least 2 of fleetSize
+// generators have the same node, when each node is a uniformly distributed
+// value of nodeBits bits (the birthday problem)
+func NodeCollisionProbability(nodeBits uint64, fleetSize int) float64 {
+	if nodeBits >= 64 {
+		nodeBits = 63
+	}
+
+	nodes := math.Ldexp(1, int(nodeBits))
+	if float64(fleetSize) > nodes {
+		return 1
+	}
+
+	unique := 1.0
+	for i := 1; i < fleetSize; i++ {
+		unique *= 1 - float64(i)/nodes
+	}
+
+	return 1 - unique
+}
+
+type overtFlakeIDSynthesizer64 struct {
+	*layoutSynthesizer
+	hardwareID HardwareID
+	processID  int
+}
+
+// NewOvertFlakeIDSynthesizer64 creates an IDGenerator that synthesizes 64-bit
+// overt-flake ids, whose node field is a hash of hardwareID and processID (see
+// NewHashedNodeSource)
+func NewOvertFlakeIDSynthesizer64(hardwareID HardwareID, processID int) IDGenerator {
+	node, _ := NewHashedNodeSource(hardwareID, processID).NodeValue(NodeBits64)
+
+	return &overtFlakeIDSynthesizer64{
+		layoutSynthesizer: &layoutSynthesizer{
+			layout: OvertFlake64Layout,
+			node:   node,
+			epoch:  OvertFlake64Layout.Epoch,
+		},
+		hardwareID: hardwareID,
+		processID:  processID,
+	}
+}
+
+// NewOvertFlakeGenerator64 creates an instance of generator (which implements
+// Generator) that generates 64-bit overt-flake ids
+func NewOvertFlakeGenerator64(hardwareID HardwareID, processID int, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewOvertFlakeIDSynthesizer64(hardwareID, processID), waitForTime, opts...)
+}
+
+func (ofid *overtFlakeIDSynthesizer64) HardwareID() HardwareID {
+	return ofid.hardwareID
+}
+
+func (ofid *overtFlakeIDSynthesizer64) ProcessID() int {
+	return ofid.processID
+}
+
+func (ofid *overtFlakeIDSynthesizer64) CollisionProbability(fleetSize int) float64 {
+	return NodeCollisionProbability(NodeBits64, fleetSize)
+}
@@ -0,0 +1,73 @@
+package flake
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvertFlakeGenerator64(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewOvertFlakeGenerator64(testHardwareID, 42, 0, WithClock(clock))
+	assert.Equal(t, OvertFlakeID64Length, gen.IDSize())
+	assert.NoError(t, gen.Layout().Validate())
+
+	hashed, ok := gen.IDGenerator().(HashedNodeIDGenerator)
+	if !assert.True(t, ok, "Expecting a HashedNodeIDGenerator") {
+		return
+	}
+
+	assert.Equal(t, 42, hashed.ProcessID())
+	assert.Equal(t, testHardwareID, hashed.HardwareID())
+	assert.True(t, hashed.Node() <= bitMask(NodeBits64))
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), ids)
+
+	for index, decoded := range decodeAll(t, gen, ids) {
+		assert.True(t, testStartTime.Equal(decoded.Time()))
+		assert.Equal(t, uint64(index), decoded.Sequence())
+		assert.Equal(t, hashed.Node(), decoded.Node())
+
+		// ids are positive int64 values
+		assert.True(t, int64(binary.BigEndian.Uint64(decoded.Bytes())) > 0)
+	}
+}
+
+func TestHashedNodeSource(t *testing.T) {
+	node, err := NewHashedNodeSource(testHardwareID, 42).NodeValue(NodeBits64)
+	assert.NoError(t, err)
+
+	// the node is stable for a hardware id and process id
+	again, err := NewHashedNodeSource(testHardwareID, 42).NodeValue(NodeBits64)
+	assert.NoError(t, err)
+	assert.Equal(t, node, again)
+
+	// and differs (in this case) for another process
+	other, err := NewHashedNodeSource(testHardwareID, 43).NodeValue(NodeBits64)
+	assert.NoError(t, err)
+	assert.NotEqual(t, node, other)
+
+	// smaller fields use the leading bits of the same hash
+	small, err := NewHashedNodeSource(testHardwareID, 42).NodeValue(4)
+	assert.NoError(t, err)
+	assert.Equal(t, node>>(NodeBits64-4), small)
+}
+
+func TestNodeCollisionProbability(t *testing.T) {
+	assert.Equal(t, 0.0, NodeCollisionProbability(NodeBits64, 0))
+	assert.Equal(t, 0.0, NodeCollisionProbability(NodeBits64, 1))
+	assert.Equal(t, 1.0/16384, NodeCollisionProbability(NodeBits64, 2))
+	assert.Equal(t, 1.0, NodeCollisionProbability(2, 5))
+
+	// the birthday paradox: 23 people, 365 days is just over 50%, and 2^14 nodes
+	// behave similarly
+	assert.InDelta(t, 0.5, NodeCollisionProbability(8, 20), 0.05)
+	assert.InDelta(t, 1-math.Exp(-100*99/2.0/16384), NodeCollisionProbability(NodeBits64, 100), 0.005)
+
+	gen := NewOvertFlakeIDSynthesizer64(testHardwareID, 42).(HashedNodeIDGenerator)
+	assert.Equal(t, NodeCollisionProbability(NodeBits64, 50), gen.CollisionProbability(50))
+}
@@ -2,32 +2,86 @@ package flake
 
 import (
 	"encoding/binary"
+	"fmt"
 	"math/big"
+	"time"
 )
 
 // overtflakeID is a wrapper around the bytes generated for an overt-flake identifer
 // 	- it implements the OvertFlakeID interface
+// 	- layout is the Layout used to extract the timestamp and sequence. nil
+// 	  implies DefaultOvertFlakeLayout
 type overtFlakeID struct {
 	idBytes []byte
+	layout  *Layout
 }
 
-// NewOvertFlakeID creates an instance of overtFlakeID which implements OvertFlakeID
+// NewOvertFlakeID creates an instance of overtFlakeID which implements OvertFlakeID.
+// The ID is assumed to use DefaultOvertFlakeLayout (16 sequence bits). Use
+// NewOvertFlakeIDWithLayout, or Generator.Decode for IDs created with other
+// layouts
 func NewOvertFlakeID(id []byte) OvertFlakeID {
 	return &overtFlakeID{
 		idBytes: id,
 	}
 }
 
+// NewOvertFlakeIDWithLayout creates an instance of overtFlakeID which
+// implements OvertFlakeID, and uses layout to extract the timestamp and
+// sequence # (ie. for IDs created by NewOvertFlakeGeneratorWithBits or
+// NewOvertFlakeGenerator53)
+func NewOvertFlakeIDWithLayout(id []byte, layout Layout) OvertFlakeID {
+	return &overtFlakeID{
+		idBytes: id,
+		layout:  &layout,
+	}
+}
+
+// Layout is the Layout used to extract the fields of the ID
+func (id *overtFlakeID) Layout() Layout {
+	if id.layout == nil {
+		return DefaultOvertFlakeLayout
+	}
+
+	return *id.layout
+}
+
 // Timestamp is when the ID was generated, and is the # of milliseconds since
 // the generator Epoch
 func (id *overtFlakeID) Timestamp() uint64 {
-	return id.Upper() >> 16
+	if id.layout == nil {
+		return id.Upper() >> 16
+	}
+
+	return id.layout.Timestamp(id.idBytes)
+}
+
+// Time is when the ID was generated, based on the Epoch of the Layout of the ID
+// (OvertoneEpochMs unless the ID was created with NewOvertFlakeIDWithLayout)
+func (id *overtFlakeID) Time() time.Time {
+	layout := id.Layout()
+	return layout.TimeForTimestamp(id.Timestamp())
 }
 
 // SequenceID represents the Nth value created during a time interval
-// (0 if the 1st interval generated)
+// (0 if the 1st interval generated). Layouts with more than 16 sequence bits
+// should use Sequence()
 func (id *overtFlakeID) SequenceID() uint16 {
-	return uint16(id.Upper() & 0xFFFF)
+	return uint16(id.Sequence())
+}
+
+// Sequence represents the Nth value created during a time interval
+func (id *overtFlakeID) Sequence() uint64 {
+	if id.layout == nil {
+		return id.Upper() & 0xFFFF
+	}
+
+	return id.layout.Sequence(id.idBytes)
+}
+
+// Node is the value that identifies the generator of the ID, and is == MachineID()
+func (id *overtFlakeID) Node() uint64 {
+	return id.Lower()
 }
 
 // HardwareID is the HardwareID assigned by the generator
@@ -71,3 +125,92 @@ func (id *overtFlakeID) ToBigInt() *big.Int {
 func (id *overtFlakeID) String() string {
 	return id.ToBigInt().String()
 }
+
+// Base62 returns the 22 character base62 (0-9A-Za-z) representation of the
+// ID. The result sorts in the same order as Bytes()
+func (id *overtFlakeID) Base62() string {
+	return base62Encoding.encode(id.idBytes)
+}
+
+// Base58 returns the 22 character base58 (Bitcoin alphabet) representation of
+// the ID. The result sorts in the same order as Bytes()
+func (id *overtFlakeID) Base58() string {
+	return base58Encoding.encode(id.idBytes)
+}
+
+// Base32 returns the 26 character Crockford base32 representation of the ID.
+// The result sorts in the same order as Bytes()
+func (id *overtFlakeID) Base32() string {
+	return crockfordBase32Encoding.encode(id.idBytes)
+}
+
+// Hex returns the 32 character lower case hexadecimal representation of the ID
+func (id *overtFlakeID) Hex() string {
+	return encodeHex(id.idBytes)
+}
+
+// UUID returns the ID formatted as a UUID (8-4-4-4-12 lower case hex)
+func (id *overtFlakeID) UUID() string {
+	return encodeUUID(id.idBytes)
+}
+
+// Format implements fmt.Formatter, supporting %s, %v, %d, %q, %x and %X
+func (id *overtFlakeID) Format(f fmt.State, verb rune) {
+	formatID(f, verb, "flake.OvertFlakeID", id.idBytes, id.String())
+}
+
+// MarshalText implements encoding.TextMarshaler using the decimal form of the ID
+func (id *overtFlakeID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler and accepts the decimal or
+// 0x prefixed hexadecimal form of an ID
+func (id *overtFlakeID) UnmarshalText(text []byte) error {
+	idBytes := make([]byte, OvertFlakeIDLength)
+	if err := unmarshalTextID(text, idBytes); err != nil {
+		return err
+	}
+
+	id.idBytes = idBytes
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler and returns a copy of the
+// bytes of the ID
+func (id *overtFlakeID) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), id.idBytes...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. data must be exactly
+// OvertFlakeIDLength bytes, and is copied
+func (id *overtFlakeID) UnmarshalBinary(data []byte) error {
+	if len(data) != OvertFlakeIDLength {
+		return ErrInvalidIDLength
+	}
+
+	id.idBytes = append([]byte(nil), data...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the format specified by
+// JSONIDFormat
+func (id *overtFlakeID) MarshalJSON() ([]byte, error) {
+	return marshalJSONID(id.idBytes)
+}
+
+// UnmarshalJSON implements json.Unmarshaler using the format specified by
+// JSONIDFormat. null is a no-op
+func (id *overtFlakeID) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		return nil
+	}
+
+	idBytes := make([]byte, OvertFlakeIDLength)
+	if err := unmarshalJSONID(data, idBytes); err != nil {
+		return err
+	}
+
+	id.idBytes = idBytes
+	return nil
+}
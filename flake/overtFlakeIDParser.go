@@ -0,0 +1,130 @@
+package flake
+
+import (
+	"math/big"
+	"strings"
+)
+
+// ParseOvertFlakeID parses the string form of an overt-flake identifier. Strings
+// prefixed with 0x (or 0X) are parsed as hexadecimal, otherwise the string is
+// parsed as decimal, which is the form produced by OvertFlakeID.String()
+func ParseOvertFlakeID(s string) (OvertFlakeID, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return ParseOvertFlakeIDHex(s)
+	}
+
+	return ParseOvertFlakeIDDecimal(s)
+}
+
+// ParseOvertFlakeIDDecimal parses a base-10 representation of an overt-flake
+// identifier. Signs, whitespace and separators are not allowed
+func ParseOvertFlakeIDDecimal(s string) (OvertFlakeID, error) {
+	return parseOvertFlakeID(s, s, 10, isDecimalDigit)
+}
+
+// ParseOvertFlakeIDHex parses a base-16 representation of an overt-flake
+// identifier. The 0x prefix is optional and both upper and lower case digits
+// are accepted
+func ParseOvertFlakeIDHex(s string) (OvertFlakeID, error) {
+	digits := s
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits = digits[2:]
+	}
+
+	return parseOvertFlakeID(s, digits, 16, isHexDigit)
+}
+
+// ParseOvertFlakeIDBase62 parses the 22 character representation produced by
+// OvertFlakeID.Base62()
+func ParseOvertFlakeIDBase62(s string) (OvertFlakeID, error) {
+	return decodeOvertFlakeID(s, base62Encoding.decode)
+}
+
+// ParseOvertFlakeIDBase58 parses the 22 character representation produced by
+// OvertFlakeID.Base58()
+func ParseOvertFlakeIDBase58(s string) (OvertFlakeID, error) {
+	return decodeOvertFlakeID(s, base58Encoding.decode)
+}
+
+// ParseOvertFlakeIDBase32 parses the 26 character Crockford base32
+// representation produced by OvertFlakeID.Base32(). Parsing is case
+// insensitive, and I, L and O are accepted as aliases of 1, 1 and 0
+func ParseOvertFlakeIDBase32(s string) (OvertFlakeID, error) {
+	return decodeOvertFlakeID(s, crockfordBase32Encoding.decode)
+}
+
+// ParseOvertFlakeIDUUID parses the 8-4-4-4-12 representation produced by
+// OvertFlakeID.UUID()
+func ParseOvertFlakeIDUUID(s string) (OvertFlakeID, error) {
+	return decodeOvertFlakeID(s, decodeUUID)
+}
+
+// MustParseOvertFlakeID is like ParseOvertFlakeID but panics if the string
+// cannot be parsed. It is intended for use with constants and in tests
+func MustParseOvertFlakeID(s string) OvertFlakeID {
+	id, err := ParseOvertFlakeID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// parseOvertFlakeID parses the digits of input using base
+func parseOvertFlakeID(input, digits string, base int, isDigit func(byte) bool) (OvertFlakeID, error) {
+	idBytes := make([]byte, OvertFlakeIDLength)
+
+	if err := decodeDigits(digits, base, isDigit, idBytes); err != nil {
+		return nil, &ParseError{Input: input, Err: err}
+	}
+
+	return NewOvertFlakeID(idBytes), nil
+}
+
+// decodeDigits validates digits against isDigit, converts them using base
+// and writes the big-endian result, left-padded with zeros, to dst
+func decodeDigits(digits string, base int, isDigit func(byte) bool, dst []byte) error {
+	if len(digits) == 0 {
+		return ErrInvalidIDSyntax
+	}
+
+	// big.Int.SetString is more forgiving than we want to be (signs, underscores)
+	// so validate every character up front
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return ErrInvalidIDSyntax
+		}
+	}
+
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return ErrInvalidIDSyntax
+	}
+
+	if value.BitLen() > len(dst)*8 {
+		return ErrIDOutOfRange
+	}
+
+	value.FillBytes(dst)
+	return nil
+}
+
+// decodeOvertFlakeID uses decode to convert s into the bytes of an
+// overt-flake ID
+func decodeOvertFlakeID(s string, decode func(string, []byte) error) (OvertFlakeID, error) {
+	idBytes := make([]byte, OvertFlakeIDLength)
+
+	if err := decode(s, idBytes); err != nil {
+		return nil, &ParseError{Input: s, Err: err}
+	}
+
+	return NewOvertFlakeID(idBytes), nil
+}
+
+func isDecimalDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDecimalDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,76 @@
+package flake
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOvertFlakeIDRoundTrip(t *testing.T) {
+	gen := NewOvertoneEpochGenerator(testHardwareID)
+	idBytes, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	id := NewOvertFlakeID(idBytes)
+
+	parsed, err := ParseOvertFlakeID(id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+
+	parsed, err = ParseOvertFlakeID(fmt.Sprintf("0x%X", id.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+
+	parsed, err = ParseOvertFlakeIDHex(fmt.Sprintf("%x", id.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), parsed.Bytes())
+}
+
+func TestParseOvertFlakeIDPadding(t *testing.T) {
+	id, err := ParseOvertFlakeIDDecimal("1")
+	assert.NoError(t, err)
+	assert.Equal(t, OvertFlakeIDLength, len(id.Bytes()))
+	assert.Equal(t, uint64(0), id.Upper())
+	assert.Equal(t, uint64(1), id.Lower())
+
+	id, err = ParseOvertFlakeIDHex("0x10000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), id.Upper())
+	assert.Equal(t, uint64(0), id.Lower())
+}
+
+func TestParseOvertFlakeIDRange(t *testing.T) {
+	// 2^128 - 1 is the largest value that fits
+	id, err := ParseOvertFlakeIDDecimal("340282366920938463463374607431768211455")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), id.Upper())
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), id.Lower())
+
+	// 2^128 does not
+	_, err = ParseOvertFlakeIDDecimal("340282366920938463463374607431768211456")
+	assert.True(t, errors.Is(err, ErrIDOutOfRange), "Expecting ErrIDOutOfRange, not %v", err)
+
+	_, err = ParseOvertFlakeIDHex("1ffffffffffffffffffffffffffffffff")
+	assert.True(t, errors.Is(err, ErrIDOutOfRange), "Expecting ErrIDOutOfRange, not %v", err)
+
+	// leading zeros do not count against the range
+	_, err = ParseOvertFlakeIDHex("0000ffffffffffffffffffffffffffffffff")
+	assert.NoError(t, err)
+}
+
+func TestParseOvertFlakeIDSyntax(t *testing.T) {
+	for _, input := range []string{"", "0x", "-1", "+1", "12_34", " 1", "1.0", "abc", "0xfg"} {
+		_, err := ParseOvertFlakeID(input)
+
+		var parseErr *ParseError
+		assert.True(t, errors.As(err, &parseErr), "Expecting a *ParseError for %q, not %v", input, err)
+		if parseErr != nil {
+			assert.Equal(t, input, parseErr.Input)
+			assert.Equal(t, ErrInvalidIDSyntax, parseErr.Err)
+		}
+	}
+
+	assert.Panics(t, func() { MustParseOvertFlakeID("nope") })
+}
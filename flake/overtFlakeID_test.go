@@ -2,6 +2,7 @@ package flake
 
 import (
 	"encoding/binary"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,3 +34,19 @@ func TestOvertFlakeID(t *testing.T) {
 	assert.NotNil(t, bigInt)
 	assert.Equal(t, bigInt.String(), id.String())
 }
+
+func TestOvertFlakeIDFormat(t *testing.T) {
+	id := MustParseOvertFlakeID("0x0000000000000001000000000000002A")
+
+	assert.Equal(t, "18446744073709551658", id.String())
+	assert.Equal(t, id.String(), fmt.Sprintf("%s", id))
+	assert.Equal(t, id.String(), fmt.Sprintf("%v", id))
+	assert.Equal(t, id.String(), fmt.Sprintf("%d", id))
+	assert.Equal(t, `"18446744073709551658"`, fmt.Sprintf("%q", id))
+	assert.Equal(t, "0000000000000001000000000000002a", fmt.Sprintf("%x", id))
+	assert.Equal(t, "0000000000000001000000000000002A", fmt.Sprintf("%X", id))
+	assert.Equal(t, "0x0000000000000001000000000000002a", fmt.Sprintf("%#x", id))
+	assert.Equal(t, "    18446744073709551658", fmt.Sprintf("%24d", id))
+	assert.Equal(t, "18446744073709551658    ", fmt.Sprintf("%-24s", id))
+	assert.Equal(t, "%!c(flake.OvertFlakeID=18446744073709551658)", fmt.Sprintf("%c", id))
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package flake
+
+import "syscall"
+
+// processAlive reports whether a process with processID is running on this
+// host (a process of another user is running, but cannot be signaled)
+func processAlive(processID int) bool {
+	if processID <= 0 {
+		return false
+	}
+
+	err := syscall.Kill(processID, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,142 @@
+package flake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomSequenceStart(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0, WithClock(clock), WithRandomSequenceStart())
+
+	starts := make(map[uint64]bool)
+	for interval := 0; interval < 20; interval++ {
+		ids, err := gen.Generate(3)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		decoded := decodeAll(t, gen, ids)
+		start := decoded[0].Sequence()
+		assert.True(t, start <= gen.MaxSequenceNumber()/2, "start %d", start)
+		starts[start] = true
+
+		// sequence #'s are consecutive within the interval
+		assert.Equal(t, start+1, decoded[1].Sequence())
+		assert.Equal(t, start+2, decoded[2].Sequence())
+
+		clock.Advance(time.Millisecond)
+	}
+
+	// 20 intervals starting at the same offset of 32768 is (practically)
+	// impossible
+	assert.True(t, len(starts) > 1)
+}
+
+func TestRandomSequenceStartFirstInterval(t *testing.T) {
+	waitForTime := testStartTime.UnixNano() / int64(time.Millisecond)
+
+	for _, lockFree := range []bool{false, true} {
+		opts := []GeneratorOption{WithClock(NewFakeClock(testStartTime)), WithRandomSequenceStart()}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+
+		// the first interval of each generator is the waitForTime interval,
+		// which is randomized like any other
+		starts := make(map[uint64]bool)
+		for generator := 0; generator < 20; generator++ {
+			gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime, opts...)
+
+			ids, err := gen.Generate(1)
+			assert.NoError(t, err)
+
+			decoded := decodeAll(t, gen, ids)
+			assert.Equal(t, uint64(waitForTime), decoded[0].Timestamp())
+			starts[decoded[0].Sequence()] = true
+		}
+
+		assert.True(t, len(starts) > 1, "lockFree = %v", lockFree)
+	}
+}
+
+func TestRandomSequenceStartExhaustion(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := NewFakeClock(testStartTime)
+		opts := []GeneratorOption{WithClock(clock), WithRandomSequenceStart()}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+
+		// 16 sequence #'s, starting at 0-7
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 4, opts...)
+
+		ids, err := gen.Generate(100)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		// every interval is used until its last sequence # before moving on
+		decoded := decodeAll(t, gen, ids)
+		for index := 1; index < len(decoded); index++ {
+			if decoded[index].Timestamp() == decoded[index-1].Timestamp() {
+				assert.Equal(t, decoded[index-1].Sequence()+1, decoded[index].Sequence())
+			} else {
+				assert.Equal(t, decoded[index-1].Timestamp()+1, decoded[index].Timestamp())
+				assert.Equal(t, gen.MaxSequenceNumber(), decoded[index-1].Sequence(), "lockFree = %v", lockFree)
+				assert.True(t, decoded[index].Sequence() <= 7)
+			}
+		}
+
+		// at least 8 ids per interval
+		assert.True(t, clock.Now().Sub(testStartTime) <= 13*time.Millisecond)
+	}
+}
+
+func TestRandomSequenceStartConcurrency(t *testing.T) {
+	const workers = 8
+	const requests = 200
+
+	for _, lockFree := range []bool{false, true} {
+		opts := []GeneratorOption{WithRandomSequenceStart()}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 4, opts...)
+
+		var wg sync.WaitGroup
+		results := make([][]byte, workers)
+
+		for worker := 0; worker < workers; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+
+				for request := 0; request < requests; request++ {
+					ids, err := gen.Generate(3)
+					if !assert.NoError(t, err) {
+						return
+					}
+					results[worker] = append(results[worker], ids...)
+				}
+			}(worker)
+		}
+		wg.Wait()
+
+		seen := make(map[ID]bool)
+		for _, ids := range results {
+			assertIncreasing(t, gen.IDSize(), ids)
+
+			for index := 0; index < len(ids); index += gen.IDSize() {
+				id, err := IDFromBytes(ids[index : index+gen.IDSize()])
+				assert.NoError(t, err)
+				assert.False(t, seen[id], "duplicate id %s", id)
+				seen[id] = true
+			}
+		}
+
+		assert.Equal(t, workers*requests*3, len(seen))
+	}
+}
@@ -0,0 +1,196 @@
+package flake
+
+import (
+	"fmt"
+	"strings"
+)
+
+//  ---------------------------------------------------------------------------
+//  Layout - Big Endian (Snowflake family)
+//  ---------------------------------------------------------------------------
+//
+//  | unused | time | shard | worker | sequence # |
+//
+//  ---------------------------------------------------------------------------
+//  Notes
+//  ---------------------------------------------------------------------------
+//  The widths of the fields vary across the family, and unused bits (if any)
+//  are the most significant. The shard and worker fields together form the node
+//  field of the Layout, ie.
+//
+//  Twitter     41-bit time | 5-bit data center | 5-bit machine | 12-bit seq #
+//  Instagram   41-bit time | 13-bit shard      |               | 10-bit seq #
+//  Discord     42-bit time | 5-bit worker      | 5-bit process | 12-bit seq #
+//  ---------------------------------------------------------------------------
+
+// InstagramEpochMs is the number of milliseconds elapsed between the Unix Epoch
+// (1/1/1970) and the epoch of Instagram ids (2011-01-01 00:00:00 +0000 UTC)
+const InstagramEpochMs = int64(1293840000000)
+
+// DiscordEpochMs is the number of milliseconds elapsed between the Unix Epoch
+// (1/1/1970) and the Discord Epoch (2015-01-01 00:00:00 +0000 UTC)
+const DiscordEpochMs = int64(1420070400000)
+
+// SnowflakeConfig describes a 64-bit identifier of the Snowflake family
+type SnowflakeConfig struct {
+	// Epoch is the # of milliseconds elapsed between the Unix Epoch and the
+	// value 0 of the time field
+	Epoch int64
+	// TimeBits is the # of bits used for the time field
+	TimeBits uint64
+	// ShardBits is the # of bits used for the shard (or data center, or worker)
+	// ID, which is the most significant part of the node field
+	ShardBits uint64
+	// WorkerBits is the # of bits used for the worker (or machine, or process)
+	// ID, which is the least significant part of the node field
+	WorkerBits uint64
+	// SequenceBits is the # of bits used for the per-interval sequence #
+	SequenceBits uint64
+}
+
+// TwitterSnowflake is the SnowflakeConfig of a Twitter Snowflake id, where the
+// shard ID is the data center ID and the worker ID is the machine ID
+var TwitterSnowflake = SnowflakeConfig{
+	Epoch:        SnowflakeEpochMs,
+	TimeBits:     41,
+	ShardBits:    5,
+	WorkerBits:   5,
+	SequenceBits: 12,
+}
+
+// InstagramSnowflake is the SnowflakeConfig of an Instagram id, which has a
+// shard ID and no worker ID
+var InstagramSnowflake = SnowflakeConfig{
+	Epoch:        InstagramEpochMs,
+	TimeBits:     41,
+	ShardBits:    13,
+	SequenceBits: 10,
+}
+
+// DiscordSnowflake is the SnowflakeConfig of a Discord id, where the shard ID
+// is the internal worker ID and the worker ID is the internal process ID
+var DiscordSnowflake = SnowflakeConfig{
+	Epoch:        DiscordEpochMs,
+	TimeBits:     42,
+	ShardBits:    5,
+	WorkerBits:   5,
+	SequenceBits: 12,
+}
+
+// ParseSnowflakePreset converts the name of a preset (twitter, instagram or
+// discord) to its SnowflakeConfig
+func ParseSnowflakePreset(name string) (SnowflakeConfig, error) {
+	switch strings.ToLower(name) {
+	case "twitter":
+		return TwitterSnowflake, nil
+	case "instagram":
+		return InstagramSnowflake, nil
+	case "discord":
+		return DiscordSnowflake, nil
+	}
+
+	return TwitterSnowflake, fmt.Errorf("unknown snowflake preset: %s", name)
+}
+
+// Layout returns the Layout of identifiers described by config
+func (config SnowflakeConfig) Layout() Layout {
+	return Layout{
+		IDSize:       8,
+		Epoch:        config.Epoch,
+		TimeBits:     config.TimeBits,
+		SequenceBits: config.SequenceBits,
+		NodeBits:     config.ShardBits + config.WorkerBits,
+		Order:        TimeNodeSequence,
+	}
+}
+
+// Validate determines if config describes a usable 64-bit identifier,
+// returning an error wrapping ErrInvalidLayout if it does not
+func (config SnowflakeConfig) Validate() error {
+	if config.ShardBits > 64 || config.WorkerBits > 64 {
+		return fmt.Errorf("%w: the shard and worker fields cannot exceed 64 bits", ErrInvalidLayout)
+	}
+
+	return config.Layout().Validate()
+}
+
+// NodeValue returns the value of the node field for shardID and workerID, or an
+// error wrapping ErrNodeOutOfRange if either is negative or too large for its
+// field
+func (config SnowflakeConfig) NodeValue(shardID, workerID int64) (uint64, error) {
+	if shardID < 0 || uint64(shardID) > bitMask(config.ShardBits) {
+		return 0, fmt.Errorf("%w: the shard id must be in the range 0-%d, not %d", ErrNodeOutOfRange, bitMask(config.ShardBits), shardID)
+	}
+
+	if workerID < 0 || uint64(workerID) > bitMask(config.WorkerBits) {
+		return 0, fmt.Errorf("%w: the worker id must be in the range 0-%d, not %d", ErrNodeOutOfRange, bitMask(config.WorkerBits), workerID)
+	}
+
+	if config.WorkerBits == 64 {
+		return uint64(workerID), nil
+	}
+
+	return uint64(shardID)<<config.WorkerBits | uint64(workerID), nil
+}
+
+type snowflakeSynthesizer struct {
+	*layoutSynthesizer
+	shardID  int64
+	workerID int64
+}
+
+// NewSnowflakeSynthesizer creates an IDGenerator that synthesizes the 64-bit
+// identifiers described by config. An error wrapping ErrInvalidLayout or
+// ErrNodeOutOfRange is returned if config is not usable, or if shardID or
+// workerID do not fit in their fields
+func NewSnowflakeSynthesizer(config SnowflakeConfig, shardID, workerID int64) (IDGenerator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	node, err := config.NodeValue(shardID, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	idGen, err := NewLayoutSynthesizer(config.Layout(), NewFixedNodeSource(node))
+	if err != nil {
+		return nil, err
+	}
+
+	return &snowflakeSynthesizer{
+		layoutSynthesizer: idGen.(*layoutSynthesizer),
+		shardID:           shardID,
+		workerID:          workerID,
+	}, nil
+}
+
+// NewSnowflakeGenerator creates an instance of generator (which implements
+// Generator) that generates the 64-bit identifiers described by config (see
+// NewSnowflakeSynthesizer)
+func NewSnowflakeGenerator(config SnowflakeConfig, shardID, workerID, waitForTime int64, opts ...GeneratorOption) (Generator, error) {
+	idGen, err := NewSnowflakeSynthesizer(config, shardID, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGenerator(idGen, waitForTime, opts...), nil
+}
+
+func (sf *snowflakeSynthesizer) ShardID() int64 {
+	return sf.shardID
+}
+
+func (sf *snowflakeSynthesizer) WorkerID() int64 {
+	return sf.workerID
+}
+
+// Decode implements IDDecoder so that Twitter Snowflake ids decode to a
+// TwitterFlakeID
+func (sf *snowflakeSynthesizer) Decode(id []byte) (DecodedID, error) {
+	if sf.layout == TwitterFlakeLayout && len(id) == TwitterFlakeIDLength {
+		return NewTwitterFlakeID(id), nil
+	}
+
+	return sf.layout.Decode(id)
+}
@@ -0,0 +1,125 @@
+package flake
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnowflakeGeneratorTwitterPreset(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	gen, err := NewSnowflakeGenerator(TwitterSnowflake, 7, 3, 0, WithClock(clock))
+	assert.NoError(t, err)
+	assert.Equal(t, TwitterFlakeLayout, gen.Layout())
+
+	expected, err := NewTwitterGenerator(3, 7, 0, WithClock(clock)).Generate(3)
+	assert.NoError(t, err)
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, ids)
+
+	decoded := decodeAll(t, gen, ids)
+	id, ok := decoded[2].(TwitterFlakeID)
+	if assert.True(t, ok, "Expecting Generator.Decode to return a TwitterFlakeID") {
+		assert.Equal(t, uint64(2), id.Sequence())
+		assert.Equal(t, uint64(7<<5|3), id.Node())
+	}
+}
+
+func TestSnowflakeGeneratorPresets(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	presets := []struct {
+		config            SnowflakeConfig
+		shardID, workerID int64
+		node              uint64
+	}{
+		{InstagramSnowflake, 5000, 0, 5000},
+		{DiscordSnowflake, 17, 30, 17<<5 | 30},
+	}
+
+	for _, preset := range presets {
+		gen, err := NewSnowflakeGenerator(preset.config, preset.shardID, preset.workerID, 0, WithClock(clock))
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		ids, err := gen.Generate(2)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		elapsed := uint64(testStartTime.UnixNano()/int64(time.Millisecond) - preset.config.Epoch)
+		value := binary.BigEndian.Uint64(ids[8:16])
+		assert.Equal(t, elapsed, value>>(preset.config.ShardBits+preset.config.WorkerBits+preset.config.SequenceBits))
+		assert.Equal(t, preset.node, (value>>preset.config.SequenceBits)&bitMask(preset.config.ShardBits+preset.config.WorkerBits))
+		assert.Equal(t, uint64(1), value&bitMask(preset.config.SequenceBits))
+
+		decoded := decodeAll(t, gen, ids)
+		assert.Equal(t, preset.node, decoded[1].Node())
+		assert.True(t, testStartTime.Equal(decoded[1].Time()))
+	}
+
+	// Instagram's 10-bit sequence allows 1024 ids per millisecond
+	gen, err := NewSnowflakeGenerator(InstagramSnowflake, 1, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1023), gen.MaxSequenceNumber())
+}
+
+func TestSnowflakeGeneratorRangeChecks(t *testing.T) {
+	invalid := []struct {
+		config            SnowflakeConfig
+		shardID, workerID int64
+		err               error
+	}{
+		{TwitterSnowflake, 32, 0, ErrNodeOutOfRange},
+		{TwitterSnowflake, 0, 32, ErrNodeOutOfRange},
+		{TwitterSnowflake, -1, 0, ErrNodeOutOfRange},
+		{TwitterSnowflake, 0, -1, ErrNodeOutOfRange},
+		{InstagramSnowflake, 8192, 0, ErrNodeOutOfRange},
+		{InstagramSnowflake, 0, 1, ErrNodeOutOfRange},
+		{DiscordSnowflake, 0, 32, ErrNodeOutOfRange},
+		{SnowflakeConfig{TimeBits: 42, ShardBits: 10, WorkerBits: 1, SequenceBits: 12}, 0, 0, ErrInvalidLayout},
+		{SnowflakeConfig{TimeBits: 0, ShardBits: 10, SequenceBits: 12}, 0, 0, ErrInvalidLayout},
+	}
+
+	for _, test := range invalid {
+		_, err := NewSnowflakeGenerator(test.config, test.shardID, test.workerID, 0)
+		assert.True(t, errors.Is(err, test.err), "Expecting %v for %+v (%d, %d), got %v", test.err, test.config, test.shardID, test.workerID, err)
+	}
+
+	// the largest values fit
+	_, err := NewSnowflakeGenerator(TwitterSnowflake, 31, 31, 0)
+	assert.NoError(t, err)
+}
+
+func TestTwitterFlakeIDSynthesizerMasksNodeIDs(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	ids, err := NewTwitterGenerator(0xFF, 0xFFFF, 0, WithClock(clock)).Generate(1)
+	assert.NoError(t, err)
+
+	// out of range ids do not overwrite the timestamp
+	id := NewTwitterFlakeID(ids)
+	assert.True(t, testStartTime.Equal(id.Time()))
+	assert.Equal(t, uint64(0x3FF), id.Node())
+}
+
+func TestParseSnowflakePreset(t *testing.T) {
+	for name, expected := range map[string]SnowflakeConfig{
+		"twitter":   TwitterSnowflake,
+		"Instagram": InstagramSnowflake,
+		"discord":   DiscordSnowflake,
+	} {
+		config, err := ParseSnowflakePreset(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, config)
+	}
+
+	_, err := ParseSnowflakePreset("sonyflake")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package flake
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+//  ---------------------------------------------------------------------------
+//  Layout - Big Endian (Sonyflake)
+//  ---------------------------------------------------------------------------
+//
+//  [0:5]   39 bits | timestamp (10ms intervals since the Sonyflake epoch)
+//  [5:6]    8 bits | sequence #
+//  [6:8]   16 bits | machine id
+//
+//  ---------------------------------------------------------------------------
+//  |   0   |   1   |   2   |   3   |   4   |   5   |   6   |   7   |
+//  ---------------------------------------------------------------------------
+//  | 0 |                38 + 1 bits                | 8 bits|    16 bits    |
+//  ---------------------------------------------------------------------------
+//  |              timestamp                        | seq # |   machine id  |
+//  ---------------------------------------------------------------------------
+//  Notes
+//  ---------------------------------------------------------------------------
+//  The most significant bit is always 0 so ids are positive as int64. At 256
+//  ids per 10ms a generator peaks at 25,600 ids/sec, but the 39-bit timestamp
+//  lasts for 174 years
+//  ---------------------------------------------------------------------------
+
+const (
+	// SonyflakeIDLength is the length, in bytes, of a Sonyflake id
+	SonyflakeIDLength = 8
+	// SonyflakeSequenceBits is the # of bits of the Sonyflake sequence #
+	SonyflakeSequenceBits uint64 = 8
+	// SonyflakeTimeUnit is the duration of a Sonyflake interval
+	SonyflakeTimeUnit = 10 * time.Millisecond
+
+	sonyflakeMachineBits = 16
+
+	// sonyflakeEpochUnits is SonyflakeEpochMs in 10ms intervals
+	sonyflakeEpochUnits = SonyflakeEpochMs / int64(SonyflakeTimeUnit/time.Millisecond)
+)
+
+// SonyflakeLayout is the Layout of a Sonyflake id
+var SonyflakeLayout = Layout{
+	IDSize:       SonyflakeIDLength,
+	Epoch:        SonyflakeEpochMs,
+	TimeBits:     39,
+	SequenceBits: SonyflakeSequenceBits,
+	NodeBits:     sonyflakeMachineBits,
+	Order:        TimeSequenceNode,
+	TimeUnit:     SonyflakeTimeUnit,
+}
+
+type sonyflakeSynthesizer struct {
+	machineID uint16
+}
+
+// NewSonyflakeSynthesizer creates an IDGenerator that synthesizes ids that are
+// compatible with Sonyflake (github.com/sony/sonyflake) using its default
+// start time
+func NewSonyflakeSynthesizer(machineID uint16) IDGenerator {
+	return &sonyflakeSynthesizer{
+		machineID: machineID,
+	}
+}
+
+// NewSonyflakeGenerator creates an instance of generator (which implements
+// Generator) that generates Sonyflake ids
+func NewSonyflakeGenerator(machineID uint16, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewSonyflakeSynthesizer(machineID), waitForTime, opts...)
+}
+
+func (sf *sonyflakeSynthesizer) MachineID() uint16 {
+	return sf.machineID
+}
+
+func (sf *sonyflakeSynthesizer) IDSize() int {
+	return SonyflakeIDLength
+}
+
+func (sf *sonyflakeSynthesizer) SequenceBitCount() uint64 {
+	return SonyflakeSequenceBits
+}
+
+func (sf *sonyflakeSynthesizer) SequenceBitMask() uint64 {
+	return bitMask(SonyflakeSequenceBits)
+}
+
+func (sf *sonyflakeSynthesizer) MaxSequenceNumber() uint64 {
+	return bitMask(SonyflakeSequenceBits)
+}
+
+func (sf *sonyflakeSynthesizer) Epoch() int64 {
+	return SonyflakeEpochMs
+}
+
+func (sf *sonyflakeSynthesizer) Layout() Layout {
+	return SonyflakeLayout
+}
+
+func (sf *sonyflakeSynthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
+	// time is the # of 10ms intervals since the Unix Epoch
+	elapsed := time - sonyflakeEpochUnits
+
+	id := uint64(elapsed)<<(SonyflakeSequenceBits+sonyflakeMachineBits) |
+		(sequence&bitMask(SonyflakeSequenceBits))<<sonyflakeMachineBits |
+		uint64(sf.machineID)
+
+	binary.BigEndian.PutUint64(buffer[index:index+SonyflakeIDLength], id)
+
+	return SonyflakeIDLength
+}
@@ -0,0 +1,89 @@
+package flake
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSonyflakeGenerator(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewSonyflakeGenerator(0x1234, 0, WithClock(clock))
+	assert.Equal(t, SonyflakeIDLength, gen.IDSize())
+	assert.NoError(t, gen.Layout().Validate())
+
+	ids, err := gen.Generate(2)
+	assert.NoError(t, err)
+
+	// the same value sonyflake.NextID() produces at testStartTime for machine id 0x1234
+	elapsed := uint64(testStartTime.Sub(time.Unix(SonyflakeEpochMs/1000, 0)) / (10 * time.Millisecond))
+	assert.Equal(t, elapsed<<24|0x1234, binary.BigEndian.Uint64(ids[0:8]))
+	assert.Equal(t, elapsed<<24|1<<16|0x1234, binary.BigEndian.Uint64(ids[8:16]))
+
+	for index, decoded := range decodeAll(t, gen, ids) {
+		assert.Equal(t, elapsed, decoded.Timestamp())
+		assert.True(t, testStartTime.Equal(decoded.Time()))
+		assert.Equal(t, uint64(index), decoded.Sequence())
+		assert.Equal(t, uint64(0x1234), decoded.Node())
+	}
+}
+
+func TestSonyflakeIntervals(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewSonyflakeGenerator(1, 0, WithClock(clock))
+
+	// ids within the same 10ms share an interval
+	clock.Advance(9 * time.Millisecond)
+	ids, err := gen.Generate(int(gen.MaxSequenceNumber()) + 1)
+	assert.NoError(t, err)
+	assert.Equal(t, testStartTime.UnixNano()/int64(time.Millisecond), gen.LastAllocatedTime())
+
+	decoded := decodeAll(t, gen, ids)
+	assert.Equal(t, decoded[0].Timestamp(), decoded[len(decoded)-1].Timestamp())
+
+	// the interval is exhausted, so the next id waits for the next 10ms
+	more, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, testStartTime.Add(10*time.Millisecond), clock.Now())
+	assert.Equal(t, decoded[0].Timestamp()+1, decodeAll(t, gen, more)[0].Timestamp())
+	assertIncreasing(t, gen.IDSize(), append(ids, more...))
+}
+
+func TestSonyflakeWaitForTime(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	// waitForTime is rounded up to the next interval
+	waitForTime := testStartTime.Add(15*time.Millisecond).UnixNano() / int64(time.Millisecond)
+	gen := NewSonyflakeGenerator(1, waitForTime, WithClock(clock), WithNotBeforeWait(time.Second))
+
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, testStartTime.Add(20*time.Millisecond), clock.Now())
+	assert.True(t, testStartTime.Add(20*time.Millisecond).Equal(decodeAll(t, gen, ids)[0].Time()))
+}
+
+func TestSonyflakeClockRegression(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+
+	var regressions []ClockRegression
+	gen := NewSonyflakeGenerator(1, 0, WithClock(clock),
+		WithClockRegressionPolicy(ClockRegressionWait, time.Second),
+		WithClockRegressionHandler(func(regression ClockRegression) {
+			regressions = append(regressions, regression)
+		}))
+
+	_, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	clock.Advance(-25 * time.Millisecond)
+	_, err = gen.Generate(1)
+	assert.NoError(t, err)
+
+	if assert.Len(t, regressions, 1) {
+		assert.Equal(t, SonyflakeTimeUnit, regressions[0].Unit)
+		assert.Equal(t, int64(3), regressions[0].Last-regressions[0].Current)
+		assert.Equal(t, 30*time.Millisecond, regressions[0].Drift())
+	}
+}
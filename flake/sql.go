@@ -0,0 +1,202 @@
+package flake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+)
+
+// SQLStorage determines how identifiers are represented in database columns
+// by the sql.Scanner and driver.Valuer implementations in this package
+type SQLStorage int
+
+const (
+	// SQLStorageDefault defers to the value of DefaultSQLStorage
+	SQLStorageDefault SQLStorage = iota
+	// SQLStorageBinary stores identifiers as 16 raw bytes, ie. BINARY(16) or BYTEA
+	SQLStorageBinary
+	// SQLStorageDecimal stores identifiers as their decimal form, ie. NUMERIC(39),
+	// DECIMAL(39,0) or a character column
+	SQLStorageDecimal
+	// SQLStorageUUID stores identifiers as 8-4-4-4-12 UUID text, ie. UUID or
+	// CHAR(36)
+	SQLStorageUUID
+)
+
+// DefaultSQLStorage is the SQLStorage used by an OvertFlakeID that is passed
+// directly to database/sql, and by wrappers whose Storage is SQLStorageDefault.
+//
+// It is intended to be set once during program initialization
+var DefaultSQLStorage = SQLStorageBinary
+
+// String returns the name of the storage mode
+func (storage SQLStorage) String() string {
+	switch storage {
+	case SQLStorageDefault:
+		return "default"
+	case SQLStorageBinary:
+		return "binary"
+	case SQLStorageDecimal:
+		return "decimal"
+	case SQLStorageUUID:
+		return "uuid"
+	}
+
+	return "unknown"
+}
+
+// resolve maps SQLStorageDefault to DefaultSQLStorage
+func (storage SQLStorage) resolve() SQLStorage {
+	if storage == SQLStorageDefault {
+		return DefaultSQLStorage
+	}
+
+	return storage
+}
+
+// valueID converts a 128-bit id to a driver.Value using storage
+func valueID(id []byte, storage SQLStorage) (driver.Value, error) {
+	if len(id) != OvertFlakeIDLength {
+		return nil, ErrInvalidIDLength
+	}
+
+	switch storage.resolve() {
+	case SQLStorageDecimal:
+		return new(big.Int).SetBytes(id).String(), nil
+	case SQLStorageUUID:
+		return encodeUUID(id), nil
+	}
+
+	return append([]byte(nil), id...), nil
+}
+
+// scanID converts src, a value returned by a database driver, into dst (a
+// 128-bit id) using storage to decide how src is interpreted
+func scanID(src interface{}, storage SQLStorage, dst []byte) error {
+	storage = storage.resolve()
+
+	var text string
+
+	switch value := src.(type) {
+	case nil:
+		return ErrNullID
+	case []byte:
+		// raw bytes are accepted for uuid storage as well as binary storage
+		// because some drivers return native UUID columns as 16 bytes
+		if storage != SQLStorageDecimal && len(value) == OvertFlakeIDLength {
+			copy(dst, value)
+			return nil
+		}
+		text = string(value)
+	case string:
+		text = value
+	case int64:
+		// drivers may return small NUMERIC values as int64
+		if storage == SQLStorageDecimal && value >= 0 {
+			new(big.Int).SetInt64(value).FillBytes(dst)
+			return nil
+		}
+		return fmt.Errorf("%w: %T using %s storage", ErrUnsupportedScanType, src, storage)
+	default:
+		return fmt.Errorf("%w: %T using %s storage", ErrUnsupportedScanType, src, storage)
+	}
+
+	var err error
+	switch storage {
+	case SQLStorageDecimal:
+		err = decodeDigits(text, 10, isDecimalDigit, dst)
+	case SQLStorageUUID:
+		err = decodeUUID(text, dst)
+	default:
+		if len(text) != OvertFlakeIDLength {
+			return ErrInvalidIDLength
+		}
+		copy(dst, text)
+	}
+
+	if err != nil {
+		return &ParseError{Input: text, Err: err}
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer using DefaultSQLStorage
+func (id *overtFlakeID) Value() (driver.Value, error) {
+	return valueID(id.idBytes, SQLStorageDefault)
+}
+
+// Scan implements sql.Scanner using DefaultSQLStorage
+func (id *overtFlakeID) Scan(src interface{}) error {
+	idBytes := make([]byte, OvertFlakeIDLength)
+	if err := scanID(src, SQLStorageDefault, idBytes); err != nil {
+		return err
+	}
+
+	id.idBytes = idBytes
+	return nil
+}
+
+// SQLOvertFlakeID adapts an OvertFlakeID to database/sql using a specific
+// storage mode, for example
+//
+//	db.Exec("INSERT INTO t (id) VALUES (?)", SQLOvertFlakeID{ID: id, Storage: SQLStorageDecimal})
+//	row.Scan(&SQLOvertFlakeID{Storage: SQLStorageDecimal})
+type SQLOvertFlakeID struct {
+	ID      OvertFlakeID
+	Storage SQLStorage
+}
+
+// Value implements driver.Valuer
+func (s SQLOvertFlakeID) Value() (driver.Value, error) {
+	if s.ID == nil {
+		return nil, ErrNullID
+	}
+
+	return valueID(s.ID.Bytes(), s.Storage)
+}
+
+// Scan implements sql.Scanner
+func (s *SQLOvertFlakeID) Scan(src interface{}) error {
+	idBytes := make([]byte, OvertFlakeIDLength)
+	if err := scanID(src, s.Storage, idBytes); err != nil {
+		return err
+	}
+
+	s.ID = NewOvertFlakeID(idBytes)
+	return nil
+}
+
+// NullOvertFlakeID represents an OvertFlakeID that may be NULL, and follows the
+// conventions of sql.NullString et al.
+type NullOvertFlakeID struct {
+	ID      OvertFlakeID
+	Storage SQLStorage
+	// Valid is true if ID is not NULL
+	Valid bool
+}
+
+// Value implements driver.Valuer
+func (n NullOvertFlakeID) Value() (driver.Value, error) {
+	if !n.Valid || n.ID == nil {
+		return nil, nil
+	}
+
+	return valueID(n.ID.Bytes(), n.Storage)
+}
+
+// Scan implements sql.Scanner
+func (n *NullOvertFlakeID) Scan(src interface{}) error {
+	if src == nil {
+		n.ID, n.Valid = nil, false
+		return nil
+	}
+
+	idBytes := make([]byte, OvertFlakeIDLength)
+	if err := scanID(src, n.Storage, idBytes); err != nil {
+		return err
+	}
+
+	n.ID, n.Valid = NewOvertFlakeID(idBytes), true
+	return nil
+}
@@ -0,0 +1,199 @@
+package flake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//	---------------------------------------------------------------------------
+//	fakeDriver is a minimal database/sql driver that stores the values passed
+//	to "INSERT" in a single column table and returns them from "SELECT", so
+//	that the Scanner and Valuer implementations can be tested without a
+//	database
+//	---------------------------------------------------------------------------
+
+type fakeDriver struct {
+	mutex sync.Mutex
+	rows  []driver.Value
+}
+
+type fakeConn struct{ driver *fakeDriver }
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+type fakeRows struct {
+	rows  []driver.Value
+	index int
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("flakefake", testDriver)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{driver: d}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.conn.driver
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "DELETE"):
+		d.rows = nil
+	case strings.HasPrefix(s.query, "INSERT"):
+		d.rows = append(d.rows, args[0])
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.conn.driver
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return &fakeRows{rows: append([]driver.Value(nil), d.rows...)}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.rows) {
+		return io.EOF
+	}
+
+	dest[0] = r.rows[r.index]
+	r.index++
+	return nil
+}
+
+// roundTrip inserts value and scans it back into dest, returning the value
+// that was stored by the driver
+func roundTrip(t *testing.T, value interface{}, dest interface{}) driver.Value {
+	db, err := sql.Open("flakefake", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("DELETE")
+	assert.NoError(t, err)
+
+	_, err = db.Exec("INSERT", value)
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.QueryRow("SELECT").Scan(dest))
+
+	testDriver.mutex.Lock()
+	defer testDriver.mutex.Unlock()
+	return testDriver.rows[0]
+}
+
+func TestSQLStorageModes(t *testing.T) {
+	id := MustParseOvertFlakeID("0x0000000000000001000000000000002A")
+
+	stored := roundTrip(t, SQLOvertFlakeID{ID: id, Storage: SQLStorageBinary}, &SQLOvertFlakeID{Storage: SQLStorageBinary})
+	assert.Equal(t, id.Bytes(), stored)
+
+	dest := &SQLOvertFlakeID{Storage: SQLStorageDecimal}
+	stored = roundTrip(t, SQLOvertFlakeID{ID: id, Storage: SQLStorageDecimal}, dest)
+	assert.Equal(t, "18446744073709551658", stored)
+	assert.Equal(t, id.Bytes(), dest.ID.Bytes())
+
+	dest = &SQLOvertFlakeID{Storage: SQLStorageUUID}
+	stored = roundTrip(t, SQLOvertFlakeID{ID: id, Storage: SQLStorageUUID}, dest)
+	assert.Equal(t, "00000000-0000-0001-0000-00000000002a", stored)
+	assert.Equal(t, id.Bytes(), dest.ID.Bytes())
+}
+
+func TestSQLDefaultStorage(t *testing.T) {
+	defer func(storage SQLStorage) { DefaultSQLStorage = storage }(DefaultSQLStorage)
+
+	id := MustParseOvertFlakeID("42")
+
+	scanned := NewOvertFlakeID(nil)
+	stored := roundTrip(t, id, scanned)
+	assert.Equal(t, id.Bytes(), stored)
+	assert.Equal(t, id.Bytes(), scanned.Bytes())
+
+	DefaultSQLStorage = SQLStorageDecimal
+
+	scanned = NewOvertFlakeID(nil)
+	stored = roundTrip(t, id, scanned)
+	assert.Equal(t, "42", stored)
+	assert.Equal(t, id.Bytes(), scanned.Bytes())
+}
+
+func TestSQLNullOvertFlakeID(t *testing.T) {
+	id := MustParseOvertFlakeID("42")
+
+	dest := &NullOvertFlakeID{Storage: SQLStorageUUID, Valid: true}
+	stored := roundTrip(t, NullOvertFlakeID{}, dest)
+	assert.Nil(t, stored)
+	assert.False(t, dest.Valid)
+	assert.Nil(t, dest.ID)
+
+	dest = &NullOvertFlakeID{Storage: SQLStorageUUID}
+	roundTrip(t, NullOvertFlakeID{ID: id, Storage: SQLStorageUUID, Valid: true}, dest)
+	assert.True(t, dest.Valid)
+	assert.Equal(t, id.Bytes(), dest.ID.Bytes())
+
+	// NULL cannot be scanned into a non-nullable ID
+	err := (&SQLOvertFlakeID{}).Scan(nil)
+	assert.Equal(t, ErrNullID, err)
+}
+
+func TestSQLScanDriverTypes(t *testing.T) {
+	id := MustParseOvertFlakeID("42")
+
+	// MySQL returns NUMERIC columns as []byte text
+	s := &SQLOvertFlakeID{Storage: SQLStorageDecimal}
+	assert.NoError(t, s.Scan([]byte("42")))
+	assert.Equal(t, id.Bytes(), s.ID.Bytes())
+
+	// a 16 character decimal is not mistaken for raw bytes
+	assert.NoError(t, s.Scan([]byte("1234567890123456")))
+	assert.Equal(t, "1234567890123456", s.ID.String())
+
+	assert.NoError(t, s.Scan(int64(42)))
+	assert.Equal(t, id.Bytes(), s.ID.Bytes())
+
+	// native UUID columns may be returned as 16 raw bytes
+	s = &SQLOvertFlakeID{Storage: SQLStorageUUID}
+	assert.NoError(t, s.Scan(id.Bytes()))
+	assert.Equal(t, id.Bytes(), s.ID.Bytes())
+
+	s = &SQLOvertFlakeID{Storage: SQLStorageUUID}
+	assert.NoError(t, s.Scan([]byte("00000000-0000-0000-0000-00000000002A")))
+	assert.Equal(t, id.Bytes(), s.ID.Bytes())
+
+	s = &SQLOvertFlakeID{Storage: SQLStorageBinary}
+	assert.Equal(t, ErrInvalidIDLength, s.Scan([]byte{1, 2, 3}))
+
+	err := s.Scan(3.14)
+	assert.True(t, errors.Is(err, ErrUnsupportedScanType), "Expecting ErrUnsupportedScanType, not %v", err)
+
+	s = &SQLOvertFlakeID{Storage: SQLStorageDecimal}
+	err = s.Scan("-42")
+	assert.True(t, errors.Is(err, ErrInvalidIDSyntax), "Expecting ErrInvalidIDSyntax, not %v", err)
+}
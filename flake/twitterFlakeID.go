@@ -0,0 +1,116 @@
+package flake
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TwitterFlakeIDLength is the length, in bytes, of a Twitter Snowflake ID
+const TwitterFlakeIDLength = 8
+
+// twitterFlakeID is the uint64 value of a Twitter Snowflake identifier
+//   - it implements the TwitterFlakeID interface
+type twitterFlakeID uint64
+
+// NewTwitterFlakeID creates an instance of twitterFlakeID, which implements
+// TwitterFlakeID, from the TwitterFlakeIDLength big-endian bytes of id
+func NewTwitterFlakeID(id []byte) TwitterFlakeID {
+	return twitterFlakeID(binary.BigEndian.Uint64(id[0:TwitterFlakeIDLength]))
+}
+
+// TwitterFlakeIDFromInt64 creates a TwitterFlakeID from its int64 representation
+func TwitterFlakeIDFromInt64(id int64) TwitterFlakeID {
+	return twitterFlakeID(uint64(id))
+}
+
+// TwitterFlakeIDFromUint64 creates a TwitterFlakeID from its uint64 representation
+func TwitterFlakeIDFromUint64(id uint64) TwitterFlakeID {
+	return twitterFlakeID(id)
+}
+
+// ParseTwitterFlakeID parses the decimal representation of a Twitter Snowflake
+// identifier. Because snowflakes are positive int64 values, values greater than
+// math.MaxInt64 are out of range
+func ParseTwitterFlakeID(s string) (TwitterFlakeID, error) {
+	if len(s) == 0 {
+		return nil, &ParseError{Input: s, Err: ErrInvalidIDSyntax}
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isDecimalDigit(s[i]) {
+			return nil, &ParseError{Input: s, Err: ErrInvalidIDSyntax}
+		}
+	}
+
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || value > math.MaxInt64 {
+		return nil, &ParseError{Input: s, Err: ErrIDOutOfRange}
+	}
+
+	return twitterFlakeID(value), nil
+}
+
+// Layout is the Layout used to extract the fields of the ID (TwitterFlakeLayout)
+func (id twitterFlakeID) Layout() Layout {
+	return TwitterFlakeLayout
+}
+
+// Timestamp is the # of milliseconds between SnowflakeEpochMs and when the ID
+// was generated
+func (id twitterFlakeID) Timestamp() uint64 {
+	return uint64(id) >> 22
+}
+
+// Time is when the ID was generated
+func (id twitterFlakeID) Time() time.Time {
+	return TwitterFlakeLayout.TimeForTimestamp(id.Timestamp())
+}
+
+// DataCenterID is the 5-bit data center id assigned by the generator
+func (id twitterFlakeID) DataCenterID() int64 {
+	return int64(id>>17) & 0x1F
+}
+
+// MachineID is the 5-bit machine id assigned by the generator
+func (id twitterFlakeID) MachineID() int64 {
+	return int64(id>>12) & 0x1F
+}
+
+// SequenceID represents the Nth value created during a time interval
+func (id twitterFlakeID) SequenceID() uint16 {
+	return uint16(id & 0xFFF)
+}
+
+// Sequence represents the Nth value created during a time interval
+func (id twitterFlakeID) Sequence() uint64 {
+	return uint64(id & 0xFFF)
+}
+
+// Node is the 10-bit combination of DataCenterID and MachineID
+func (id twitterFlakeID) Node() uint64 {
+	return uint64(id>>12) & 0x3FF
+}
+
+// Int64 returns the ID as an int64
+func (id twitterFlakeID) Int64() int64 {
+	return int64(id)
+}
+
+// Uint64 returns the ID as a uint64
+func (id twitterFlakeID) Uint64() uint64 {
+	return uint64(id)
+}
+
+// Bytes is the big-endian []byte representation of the ID
+func (id twitterFlakeID) Bytes() []byte {
+	idBytes := make([]byte, TwitterFlakeIDLength)
+	binary.BigEndian.PutUint64(idBytes, uint64(id))
+	return idBytes
+}
+
+// String returns the decimal representation of the ID
+func (id twitterFlakeID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -33,25 +33,31 @@ type twitterFlakeIDSynthesizer struct {
 	dataCenterID int64
 }
 
-// NewTwitterFlakeIDSynthesizer creates an instance of generator (which implements Generator) and
-// allows the # of sequence bits to be specified (16 is standard)
+// NewTwitterFlakeIDSynthesizer creates an IDGenerator that synthesizes Twitter
+// Snowflake ids. Only the 5 least-significant bits of machineID and
+// dataCenterID are used (so they cannot overwrite other fields)
+//
+// Deprecated: ids out of range are silently truncated. Use
+// NewSnowflakeSynthesizer with TwitterSnowflake, which returns an error
+// wrapping ErrNodeOutOfRange for them
 func NewTwitterFlakeIDSynthesizer(machineID, dataCenterID int64) IDGenerator {
 	return &twitterFlakeIDSynthesizer{
 		epoch:        SnowflakeEpochMs,
 		sequenceBits: 12,
 		idBits:       10,
 		sequenceMask: uint64(int64(-1) ^ (int64(-1) << 12)),
-		machineID:    machineID,
-		dataCenterID: dataCenterID & 0xFFFF,
+		machineID:    machineID & 0x1F,
+		dataCenterID: dataCenterID & 0x1F,
 	}
 }
 
 // NewTwitterGenerator creates an instance of generator (which implements Generator.)
-func NewTwitterGenerator(machineID, dataCenterID, waitForTime int64) Generator {
-	return &generator{
-		idGen:    NewTwitterFlakeIDSynthesizer(machineID, dataCenterID),
-		lastTime: waitForTime,
-	}
+//
+// Deprecated: ids out of range are silently truncated. Use
+// NewSnowflakeGenerator with TwitterSnowflake, which returns an error
+// wrapping ErrNodeOutOfRange for them
+func NewTwitterGenerator(machineID, dataCenterID, waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewTwitterFlakeIDSynthesizer(machineID, dataCenterID), waitForTime, opts...)
 }
 
 func (ofid *twitterFlakeIDSynthesizer) MachineID() int64 {
@@ -63,7 +69,7 @@ func (ofid *twitterFlakeIDSynthesizer) DataCenterID() int64 {
 }
 
 func (ofid *twitterFlakeIDSynthesizer) IDSize() int {
-	return 8
+	return TwitterFlakeIDLength
 }
 
 func (ofid *twitterFlakeIDSynthesizer) SequenceBitCount() uint64 {
@@ -82,6 +88,19 @@ func (ofid *twitterFlakeIDSynthesizer) Epoch() int64 {
 	return ofid.epoch
 }
 
+func (ofid *twitterFlakeIDSynthesizer) Layout() Layout {
+	return TwitterFlakeLayout
+}
+
+// Decode implements IDDecoder so that Generator.Decode returns a TwitterFlakeID
+func (ofid *twitterFlakeIDSynthesizer) Decode(id []byte) (DecodedID, error) {
+	if len(id) != TwitterFlakeIDLength {
+		return nil, ErrInvalidIDLength
+	}
+
+	return NewTwitterFlakeID(id), nil
+}
+
 func (ofid *twitterFlakeIDSynthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
 	// time is Unix Epoch (note that this is inefficient in that delta has to be calculated for
 	// each id, when the generator could do the calculation + shift 1 time per allocate)
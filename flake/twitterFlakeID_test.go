@@ -0,0 +1,67 @@
+package flake
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwitterFlakeID(t *testing.T) {
+	gen := NewTwitterGenerator(3, 7, 0)
+
+	ids, err := gen.Generate(2)
+	assert.NoError(t, err)
+
+	decoded, err := gen.Decode(ids[8:16])
+	assert.NoError(t, err)
+
+	id, ok := decoded.(TwitterFlakeID)
+	assert.True(t, ok, "Expecting Generator.Decode to return a TwitterFlakeID")
+	if !ok {
+		return
+	}
+
+	assert.Equal(t, int64(3), id.MachineID())
+	assert.Equal(t, int64(7), id.DataCenterID())
+	assert.Equal(t, uint16(1), id.SequenceID())
+	assert.Equal(t, uint64(gen.LastAllocatedTime()-SnowflakeEpochMs), id.Timestamp())
+	assert.Equal(t, gen.LastAllocatedTime(), id.Time().UnixNano()/int64(time.Millisecond))
+	assert.Equal(t, ids[8:16], id.Bytes())
+	assert.Equal(t, TwitterFlakeLayout, id.Layout())
+
+	assert.True(t, id.Int64() > 0)
+	assert.Equal(t, uint64(id.Int64()), id.Uint64())
+	assert.Equal(t, id, TwitterFlakeIDFromInt64(id.Int64()))
+	assert.Equal(t, id, TwitterFlakeIDFromUint64(id.Uint64()))
+	assert.Equal(t, strconv.FormatInt(id.Int64(), 10), id.String())
+
+	parsed, err := ParseTwitterFlakeID(id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+}
+
+func TestTwitterFlakeIDKnownValue(t *testing.T) {
+	// a tweet id from the public timeline
+	id, err := ParseTwitterFlakeID("1212092628029698048")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2019-12-31T19:26:16.771Z", id.Time().UTC().Format("2006-01-02T15:04:05.000Z"))
+	assert.Equal(t, int64(1212092628029698048), id.Int64())
+}
+
+func TestParseTwitterFlakeIDErrors(t *testing.T) {
+	for _, input := range []string{"", "-1", "+1", "0x10", "1 "} {
+		_, err := ParseTwitterFlakeID(input)
+		assert.True(t, errors.Is(err, ErrInvalidIDSyntax), "Expecting ErrInvalidIDSyntax for %q, not %v", input, err)
+	}
+
+	// > math.MaxInt64
+	_, err := ParseTwitterFlakeID("9223372036854775808")
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+
+	_, err = ParseTwitterFlakeID("99999999999999999999")
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+}
@@ -1,6 +1,15 @@
 package flake
 
-import "math/big"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
 
 // IDGenerator encapsulates the data and functionality that is
 // specific to ID generation, and is used by a Generator to create actual
@@ -29,6 +38,12 @@ type IDGenerator interface {
 	// MaxSequenceNumber is an alias for SequenceBitMask that is used when we
 	// want to refer to it as an absolute # rather than a mask. For readability
 	MaxSequenceNumber() uint64
+
+	// Layout describes how the fields of the identifiers created by the
+	// generator are arranged. Layout().Unit() is the duration of the
+	// intervals used by the hosting Generator, and the time passed to
+	// SynthesizeID is the # of those intervals since the Unix Epoch
+	Layout() Layout
 }
 
 // Generator is the base interface for flake ID generators and as a convienence
@@ -42,15 +57,61 @@ type Generator interface {
 	// components
 	IDGenerator() IDGenerator
 
-	// LastAllocatedTime is the last Unix Epoch value that one or more ids
-	// are known to have been generated
+	// LastAllocatedTime is the last Unix Epoch value (in milliseconds) that
+	// one or more ids are known to have been generated
 	LastAllocatedTime() int64
 
-	// Generate generates count overt-flake identifiers
+	// Ready returns a channel that is closed once ids are available, which is
+	// when the clock reaches the waitForTime of the generator
+	Ready() <-chan struct{}
+
+	// Generate generates count overt-flake identifiers (in increasing order)
 	Generate(count int) ([]byte, error)
 
-	// GenerateAsStream allocates and returns ids in chunks (based on the size of buffer) via a callback
+	// GenerateAsStream allocates and returns ids (in increasing order) in chunks (based on the size of buffer) via a callback
 	GenerateAsStream(count int, buffer []byte, callback func(int, []byte) error) (totalAllocated int, err error)
+
+	// GenerateContext is Generate, but returns ctx.Err() if ctx is done while
+	// waiting for the next interval, for the clock to catch up, or for
+	// waitForTime
+	GenerateContext(ctx context.Context, count int) ([]byte, error)
+
+	// GenerateAsStreamContext is GenerateAsStream, but returns ctx.Err() if ctx
+	// is done while waiting (see GenerateContext) or between chunks
+	GenerateAsStreamContext(ctx context.Context, count int, buffer []byte, callback func(int, []byte) error) (totalAllocated int, err error)
+
+	// Decode provides access to the fields of an id created by the generator
+	// using the Layout of its IDGenerator
+	Decode(id []byte) (DecodedID, error)
+}
+
+// IDDecoder is optionally implemented by an IDGenerator whose identifiers have
+// a specialized DecodedID representation. Generator.Decode uses it in place of
+// Layout.Decode
+type IDDecoder interface {
+	Decode(id []byte) (DecodedID, error)
+}
+
+// DecodedID provides access to the fields of an identifier as described by
+// a Layout
+type DecodedID interface {
+	// Layout is the Layout used to extract the fields of the ID
+	Layout() Layout
+
+	// Timestamp is the # of intervals (milliseconds) between the Layout Epoch
+	// and when the ID was generated
+	Timestamp() uint64
+	// Time is when the ID was generated
+	Time() time.Time
+	// Sequence represents the Nth value created during a time interval
+	Sequence() uint64
+	// Node is the value that identifies the generator of the ID
+	Node() uint64
+
+	// Bytes is the []byte representation of the ID
+	Bytes() []byte
+	// String returns the decimal representation of the ID
+	String() string
 }
 
 // OvertFlakeIDGenerator extends IDGenerator adding overt-flake identifier specific concepts
@@ -63,6 +124,19 @@ type OvertFlakeIDGenerator interface {
 	ProcessID() int
 }
 
+// HashedNodeIDGenerator extends OvertFlakeIDGenerator for generators whose
+// node field is a hash of the hardware id and process id, and so may collide
+// with the node of another generator
+type HashedNodeIDGenerator interface {
+	OvertFlakeIDGenerator
+
+	// Node is the value of the node field of every identifier
+	Node() uint64
+	// CollisionProbability is the probability that at least 2 generators in a
+	// fleet of fleetSize generators have the same node
+	CollisionProbability(fleetSize int) float64
+}
+
 // HardwareID is an alias for []byte
 type HardwareID []byte
 
@@ -75,6 +149,8 @@ type HardwareIDProvider interface {
 // OvertFlakeID is an interface that provides access to the components and
 // alternate representations of an overt-flake identifier
 type OvertFlakeID interface {
+	DecodedID
+
 	// Timestamp is when the ID was generated, and is the # of milliseconds since
 	// the generator Epoch
 	Timestamp() uint64
@@ -101,4 +177,58 @@ type OvertFlakeID interface {
 
 	// String returns the big.Int string representation of the ID
 	String() string
+
+	// Base62 returns the 22 character base62 (0-9A-Za-z) representation of the
+	// ID. The result sorts in the same order as Bytes()
+	Base62() string
+	// Base58 returns the 22 character base58 (Bitcoin alphabet) representation
+	// of the ID. The result sorts in the same order as Bytes()
+	Base58() string
+	// Base32 returns the 26 character Crockford base32 representation of the ID.
+	// The result sorts in the same order as Bytes()
+	Base32() string
+	// Hex returns the 32 character lower case hexadecimal representation of the ID
+	Hex() string
+	// UUID returns the ID formatted as a UUID (8-4-4-4-12 lower case hex)
+	UUID() string
+
+	// Format implements fmt.Formatter, supporting %s, %v, %d, %q, %x and %X
+	fmt.Formatter
+
+	// The text form of an ID is its decimal representation
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+
+	// The binary form of an ID is its OvertFlakeIDLength bytes
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+
+	// The JSON form of an ID is a string, formatted according to JSONIDFormat.
+	// To unmarshal into an OvertFlakeID the value must be non-nil, for example
+	// NewOvertFlakeID(nil)
+	json.Marshaler
+	json.Unmarshaler
+
+	// IDs are stored in databases according to DefaultSQLStorage. Use
+	// SQLOvertFlakeID or NullOvertFlakeID to select a storage mode per column
+	driver.Valuer
+	sql.Scanner
+}
+
+// TwitterFlakeID is an interface that provides access to the components and
+// alternate representations of a Twitter Snowflake identifier
+type TwitterFlakeID interface {
+	DecodedID
+
+	// DataCenterID is the data center id assigned by the generator
+	DataCenterID() int64
+	// MachineID is the machine id assigned by the generator
+	MachineID() int64
+	// SequenceID represents the Nth value created during a time interval (0 for the 1st)
+	SequenceID() uint16
+
+	// Int64 returns the ID as an int64
+	Int64() int64
+	// Uint64 returns the ID as a uint64
+	Uint64() uint64
 }
@@ -0,0 +1,127 @@
+package flake
+
+import (
+	"bytes"
+	"time"
+)
+
+// ULIDLength is the length, in bytes, of a ULID
+const ULIDLength = 16
+
+// ULIDLayout is the Layout of a ULID: a 48-bit timestamp (milliseconds since
+// the Unix Epoch) followed by 80 bits of randomness. The layout has no
+// sequence field, so its sequence and node fields are the upper 16 and lower
+// 64 bits of the randomness respectively
+var ULIDLayout = Layout{
+	IDSize:       ULIDLength,
+	Epoch:        UnixEpochMs,
+	TimeBits:     48,
+	SequenceBits: 16,
+	NodeBits:     64,
+	Order:        TimeSequenceNode,
+}
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier. Its
+// text form is 26 Crockford base32 characters, which sort the same way as the
+// bytes of the ULID
+type ULID [ULIDLength]byte
+
+// ULIDFromBytes copies a 16 byte ULID into a ULID
+func ULIDFromBytes(b []byte) (ulid ULID, err error) {
+	if len(b) != ULIDLength {
+		return ulid, ErrInvalidIDLength
+	}
+
+	copy(ulid[:], b)
+
+	return ulid, nil
+}
+
+// ParseULID parses the 26 character Crockford base32 form of a ULID. Lower
+// case, and the letters I, L and O (as 1, 1 and 0), are accepted
+func ParseULID(s string) (ulid ULID, err error) {
+	if err = crockfordBase32Encoding.decode(s, ulid[:]); err != nil {
+		return ULID{}, &ParseError{Input: s, Err: err}
+	}
+
+	return ulid, nil
+}
+
+// MustParseULID is like ParseULID but panics if the string cannot be parsed.
+// It is intended for use with constants and in tests
+func MustParseULID(s string) ULID {
+	ulid, err := ParseULID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return ulid
+}
+
+// String returns the 26 character Crockford base32 form of the ULID
+func (ulid ULID) String() string {
+	return crockfordBase32Encoding.encode(ulid[:])
+}
+
+// Bytes returns a copy of the bytes of the ULID
+func (ulid ULID) Bytes() []byte {
+	return append([]byte(nil), ulid[:]...)
+}
+
+// Compare returns -1, 0 or 1 when ulid is less than, equal to or greater than
+// other
+func (ulid ULID) Compare(other ULID) int {
+	return bytes.Compare(ulid[:], other[:])
+}
+
+// IsZero determines if the ULID is the zero value
+func (ulid ULID) IsZero() bool {
+	return ulid == ULID{}
+}
+
+// Layout returns ULIDLayout
+func (ulid ULID) Layout() Layout {
+	return ULIDLayout
+}
+
+// Timestamp returns the # of milliseconds since the Unix Epoch
+func (ulid ULID) Timestamp() uint64 {
+	return ULIDLayout.Timestamp(ulid[:])
+}
+
+// Time returns the time the ULID was generated (to the millisecond)
+func (ulid ULID) Time() time.Time {
+	return ULIDLayout.Time(ulid[:])
+}
+
+// Sequence returns the upper 16 bits of the randomness (see ULIDLayout)
+func (ulid ULID) Sequence() uint64 {
+	return ULIDLayout.Sequence(ulid[:])
+}
+
+// Node returns the lower 64 bits of the randomness (see ULIDLayout)
+func (ulid ULID) Node() uint64 {
+	return ULIDLayout.Node(ulid[:])
+}
+
+// MarshalText implements encoding.TextMarshaler using the base32 form
+func (ulid ULID) MarshalText() ([]byte, error) {
+	return []byte(ulid.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using the base32 form
+func (ulid *ULID) UnmarshalText(text []byte) (err error) {
+	*ulid, err = ParseULID(string(text))
+	return
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (ulid ULID) MarshalBinary() ([]byte, error) {
+	return ulid.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (ulid *ULID) UnmarshalBinary(data []byte) (err error) {
+	*ulid, err = ULIDFromBytes(data)
+	return
+}
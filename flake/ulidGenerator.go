@@ -0,0 +1,126 @@
+package flake
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"sync"
+)
+
+//  ---------------------------------------------------------------------------
+//  Layout - Big Endian
+//  ---------------------------------------------------------------------------
+//
+//  [0:6]   48 bits | timestamp (milliseconds since the Unix Epoch)
+//  [6:16]  80 bits | randomness (per-interval random base + sequence #)
+//
+//  ---------------------------------------------------------------------------
+//  Notes
+//  ---------------------------------------------------------------------------
+//  Each interval has a random base, derived from the interval and a random
+//  key chosen when the synthesizer is created, and the randomness of each id
+//  is base + sequence #. So ids from one generator are monotonic within a
+//  millisecond (as with the monotonic ULID factories), and the base does not
+//  depend on the order in which ids are synthesized. The most significant
+//  bit of the base is 0 so that adding the sequence # never overflows
+//  ---------------------------------------------------------------------------
+
+// ULIDSequenceBits is the # of sequence bits used by the ULID generator, ie.
+// the maximum # of ULIDs per millisecond is 2^ULIDSequenceBits
+const ULIDSequenceBits uint64 = 16
+
+type ulidSynthesizer struct {
+	key [32]byte
+
+	// the random base of the most recent interval
+	mutex    sync.Mutex
+	baseTime int64
+	baseHi   uint64
+	baseLo   uint64
+}
+
+// NewULIDSynthesizer creates an IDGenerator that synthesizes ULIDs
+func NewULIDSynthesizer() IDGenerator {
+	ulid := &ulidSynthesizer{baseTime: -1}
+
+	if _, err := io.ReadFull(rand.Reader, ulid.key[:]); err != nil {
+		// there is no safe fallback
+		panic(err)
+	}
+
+	return ulid
+}
+
+// NewULIDGenerator creates an instance of generator (which implements
+// Generator) that generates ULIDs
+func NewULIDGenerator(waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewULIDSynthesizer(), waitForTime, opts...)
+}
+
+func (ulid *ulidSynthesizer) IDSize() int {
+	return ULIDLength
+}
+
+func (ulid *ulidSynthesizer) SequenceBitCount() uint64 {
+	return ULIDSequenceBits
+}
+
+func (ulid *ulidSynthesizer) SequenceBitMask() uint64 {
+	return bitMask(ULIDSequenceBits)
+}
+
+func (ulid *ulidSynthesizer) MaxSequenceNumber() uint64 {
+	return bitMask(ULIDSequenceBits)
+}
+
+func (ulid *ulidSynthesizer) Epoch() int64 {
+	return UnixEpochMs
+}
+
+func (ulid *ulidSynthesizer) Layout() Layout {
+	return ULIDLayout
+}
+
+// Decode implements IDDecoder so that Generator.Decode returns a ULID
+func (ulid *ulidSynthesizer) Decode(id []byte) (DecodedID, error) {
+	decoded, err := ULIDFromBytes(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+func (ulid *ulidSynthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
+	baseHi, baseLo := ulid.base(time)
+
+	lo, carry := bits.Add64(baseLo, sequence&bitMask(ULIDSequenceBits), 0)
+	hi := baseHi + carry
+
+	binary.BigEndian.PutUint64(buffer[index:index+8], uint64(time)<<16|hi)
+	binary.BigEndian.PutUint64(buffer[index+8:index+16], lo)
+
+	return ULIDLength
+}
+
+// base returns the random base (upper 16 and lower 64 bits) for the interval
+func (ulid *ulidSynthesizer) base(time int64) (uint64, uint64) {
+	ulid.mutex.Lock()
+	defer ulid.mutex.Unlock()
+
+	if time != ulid.baseTime {
+		var input [40]byte
+		copy(input[:32], ulid.key[:])
+		binary.BigEndian.PutUint64(input[32:], uint64(time))
+
+		sum := sha256.Sum256(input[:])
+
+		ulid.baseTime = time
+		ulid.baseHi = uint64(binary.BigEndian.Uint16(sum[0:2]) & 0x7FFF)
+		ulid.baseLo = binary.BigEndian.Uint64(sum[2:10])
+	}
+
+	return ulid.baseHi, ulid.baseLo
+}
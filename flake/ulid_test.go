@@ -0,0 +1,95 @@
+package flake
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseULID(t *testing.T) {
+	// the example from the ULID specification
+	ulid, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1469922850259), ulid.Timestamp())
+	assert.Equal(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", ulid.String())
+	assert.True(t, time.Unix(1469922850, 259*int64(time.Millisecond)).Equal(ulid.Time()))
+
+	// lower case and the confusable letters are accepted
+	lower, err := ParseULID(strings.ToLower("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+	assert.NoError(t, err)
+	assert.Equal(t, ulid, lower)
+
+	confused, err := ParseULID("O1ARZ3NDEKTSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, ulid, confused)
+
+	for _, s := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+		_, err = ParseULID(s)
+		assert.True(t, errors.Is(err, ErrInvalidIDSyntax), s)
+	}
+
+	// the largest ULID is 7ZZZZZZZZZZZZZZZZZZZZZZZZZ
+	_, err = ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	assert.NoError(t, err)
+	_, err = ParseULID("80000000000000000000000000")
+	assert.True(t, errors.Is(err, ErrIDOutOfRange))
+
+	assert.Panics(t, func() { MustParseULID("invalid") })
+}
+
+func TestULIDMarshaling(t *testing.T) {
+	ulid := MustParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	data, err := json.Marshal(ulid)
+	assert.NoError(t, err)
+	assert.Equal(t, `"01ARZ3NDEKTSV4RRFFQ69G5FAV"`, string(data))
+
+	var unmarshaled ULID
+	assert.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, ulid, unmarshaled)
+
+	binary, err := ulid.MarshalBinary()
+	assert.NoError(t, err)
+	unmarshaled = ULID{}
+	assert.NoError(t, unmarshaled.UnmarshalBinary(binary))
+	assert.Equal(t, ulid, unmarshaled)
+	assert.Equal(t, ErrInvalidIDLength, unmarshaled.UnmarshalBinary(binary[1:]))
+}
+
+func TestULIDGenerator(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewULIDGenerator(0, WithClock(clock))
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), ids)
+
+	decoded := decodeAll(t, gen, ids)
+	for _, id := range decoded {
+		ulid := id.(ULID)
+		assert.True(t, testStartTime.Equal(ulid.Time()))
+
+		parsed, err := ParseULID(ulid.String())
+		assert.NoError(t, err)
+		assert.Equal(t, ulid, parsed)
+	}
+
+	// monotonic within the millisecond, across requests
+	more, err := gen.Generate(2)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), append(ids[len(ids)-ULIDLength:], more...))
+
+	first := decoded[0].(ULID)
+	last := decodeAll(t, gen, more)[1].(ULID)
+	assert.Equal(t, first.Node()+4, last.Node())
+
+	// a new interval has a new random base
+	clock.Advance(time.Millisecond)
+	next, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Node()+5, decodeAll(t, gen, next)[0].(ULID).Node())
+}
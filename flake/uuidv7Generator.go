@@ -0,0 +1,184 @@
+package flake
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	mathrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+//  ---------------------------------------------------------------------------
+//  Layout - Big Endian (RFC 9562 UUIDv7)
+//  ---------------------------------------------------------------------------
+//
+//  [0:6]   48 bits | timestamp (milliseconds since the Unix Epoch)
+//  [6:8]    4 bits | version (0111)
+//          12 bits | a per-interval sequence # (counter)
+//  [8:16]   2 bits | variant (10)
+//          62 bits | random
+//
+//  ---------------------------------------------------------------------------
+//  Notes
+//  ---------------------------------------------------------------------------
+//  The sequence # is the fixed-length dedicated counter described by RFC 9562
+//  (Method 1), so ids from one generator are strictly increasing within a
+//  millisecond. The random bits make ids from different generators unique
+//  without any node configuration
+//  ---------------------------------------------------------------------------
+
+const (
+	// UUIDv7Length is the length, in bytes, of a UUIDv7
+	UUIDv7Length = 16
+	// UUIDv7SequenceBits is the # of bits of the UUIDv7 counter
+	UUIDv7SequenceBits uint64 = 12
+
+	uuidv7Version = 0x7000
+	uuidv7Variant = 0x80
+
+	// the random bits of 64 ids are read from the random source at once
+	uuidv7RandomBufferSize = 64 * 8
+)
+
+// UUIDv7Layout is the Layout of a UUIDv7. The layout has no version or variant
+// fields, so its sequence field includes the version bits and its node field
+// includes the variant and random bits. The time field is exact, so it can be
+// used for time range queries (MinIDForTime and MaxIDForTime)
+var UUIDv7Layout = Layout{
+	IDSize:       UUIDv7Length,
+	Epoch:        UnixEpochMs,
+	TimeBits:     48,
+	SequenceBits: 16,
+	NodeBits:     64,
+	Order:        TimeSequenceNode,
+}
+
+// uuidv7Synthesizer is an implementation of IDGenerator for UUIDv7s
+//
+//	random buffers the random source (crypto/rand), so that most ids do not
+//		require a system call. It is guarded by mutex as ids may be
+//		synthesized concurrently
+type uuidv7Synthesizer struct {
+	mutex  sync.Mutex
+	random *bufio.Reader
+}
+
+// NewUUIDv7Synthesizer creates an IDGenerator that synthesizes RFC 9562
+// UUIDv7s
+func NewUUIDv7Synthesizer() IDGenerator {
+	return newUUIDv7Synthesizer(rand.Reader)
+}
+
+// newUUIDv7Synthesizer creates a uuidv7Synthesizer whose random bits are read
+// from random
+func newUUIDv7Synthesizer(random io.Reader) *uuidv7Synthesizer {
+	return &uuidv7Synthesizer{
+		random: bufio.NewReaderSize(random, uuidv7RandomBufferSize),
+	}
+}
+
+// NewUUIDv7Generator creates an instance of generator (which implements
+// Generator) that generates UUIDv7s
+func NewUUIDv7Generator(waitForTime int64, opts ...GeneratorOption) Generator {
+	return NewGenerator(NewUUIDv7Synthesizer(), waitForTime, opts...)
+}
+
+func (uuid *uuidv7Synthesizer) IDSize() int {
+	return UUIDv7Length
+}
+
+func (uuid *uuidv7Synthesizer) SequenceBitCount() uint64 {
+	return UUIDv7SequenceBits
+}
+
+func (uuid *uuidv7Synthesizer) SequenceBitMask() uint64 {
+	return bitMask(UUIDv7SequenceBits)
+}
+
+func (uuid *uuidv7Synthesizer) MaxSequenceNumber() uint64 {
+	return bitMask(UUIDv7SequenceBits)
+}
+
+func (uuid *uuidv7Synthesizer) Epoch() int64 {
+	return UnixEpochMs
+}
+
+func (uuid *uuidv7Synthesizer) Layout() Layout {
+	return UUIDv7Layout
+}
+
+// Decode implements IDDecoder so that the sequence # and node exclude the
+// version and variant bits
+func (uuid *uuidv7Synthesizer) Decode(id []byte) (DecodedID, error) {
+	if len(id) != UUIDv7Length {
+		return nil, ErrInvalidIDLength
+	}
+
+	return uuidv7ID(id), nil
+}
+
+func (uuid *uuidv7Synthesizer) SynthesizeID(buffer []byte, index int, time int64, sequence uint64) int {
+	id := buffer[index : index+UUIDv7Length]
+
+	// the random bits are written first, and then overwritten by the rest
+	uuid.readRandom(id[8:])
+
+	binary.BigEndian.PutUint64(id[0:8], uint64(time)<<16|uuidv7Version|(sequence&bitMask(UUIDv7SequenceBits)))
+	id[8] = (id[8] & 0x3F) | uuidv7Variant
+
+	return UUIDv7Length
+}
+
+// readRandom fills p from the random source. As with the random sequence
+// start of a generator, a failure is not reported: the bits come from
+// math/rand instead, which still makes ids from different generators unique
+// but not unpredictable
+func (uuid *uuidv7Synthesizer) readRandom(p []byte) {
+	uuid.mutex.Lock()
+	_, err := io.ReadFull(uuid.random, p)
+	uuid.mutex.Unlock()
+
+	if err != nil {
+		for index := 0; index < len(p); index += 8 {
+			var random [8]byte
+			binary.BigEndian.PutUint64(random[:], mathrand.Uint64())
+			copy(p[index:], random[:])
+		}
+	}
+}
+
+// uuidv7ID is the DecodedID of a UUIDv7
+type uuidv7ID []byte
+
+func (id uuidv7ID) Layout() Layout {
+	return UUIDv7Layout
+}
+
+func (id uuidv7ID) Timestamp() uint64 {
+	return UUIDv7Layout.Timestamp(id)
+}
+
+func (id uuidv7ID) Time() time.Time {
+	return UUIDv7Layout.Time(id)
+}
+
+// Sequence is the 12-bit counter (without the version bits)
+func (id uuidv7ID) Sequence() uint64 {
+	return UUIDv7Layout.Sequence(id) & bitMask(UUIDv7SequenceBits)
+}
+
+// Node is the 62 random bits (without the variant bits)
+func (id uuidv7ID) Node() uint64 {
+	return UUIDv7Layout.Node(id) & bitMask(62)
+}
+
+func (id uuidv7ID) Bytes() []byte {
+	return id
+}
+
+// String returns the canonical (8-4-4-4-12 hex) form of the UUID
+func (id uuidv7ID) String() string {
+	return encodeUUID(id)
+}
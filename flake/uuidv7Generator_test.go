@@ -0,0 +1,76 @@
+package flake
+
+import (
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDv7Generator(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewUUIDv7Generator(0, WithClock(clock))
+	assert.Equal(t, UUIDv7Length, gen.IDSize())
+
+	ids, err := gen.Generate(3)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), ids)
+
+	for index, decoded := range decodeAll(t, gen, ids) {
+		id := decoded.Bytes()
+
+		// version 7, variant 10
+		assert.Equal(t, byte(0x70), id[6]&0xF0)
+		assert.Equal(t, byte(0x80), id[8]&0xC0)
+
+		assert.True(t, testStartTime.Equal(decoded.Time()))
+		assert.Equal(t, uint64(testStartTime.UnixNano()/int64(time.Millisecond)), decoded.Timestamp())
+		assert.Equal(t, uint64(index), decoded.Sequence())
+		assert.Equal(t, encodeUUID(id), decoded.String())
+		assert.Equal(t, byte('7'), decoded.String()[14])
+	}
+}
+
+func TestUUIDv7GeneratorRandomBits(t *testing.T) {
+	gen := NewUUIDv7Generator(0)
+
+	ids, err := gen.Generate(2)
+	assert.NoError(t, err)
+
+	decoded := decodeAll(t, gen, ids)
+	assert.NotEqual(t, decoded[0].Node(), decoded[1].Node())
+	assert.True(t, decoded[0].Node() <= bitMask(62))
+}
+
+func TestUUIDv7GeneratorRandomFailure(t *testing.T) {
+	synth := newUUIDv7Synthesizer(iotest.ErrReader(errors.New("no entropy")))
+	gen := NewGenerator(synth, 0)
+
+	var ids []byte
+	assert.NotPanics(t, func() {
+		var err error
+		ids, err = gen.Generate(2)
+		assert.NoError(t, err)
+	})
+
+	decoded := decodeAll(t, gen, ids)
+	assert.NotEqual(t, decoded[0].Node(), decoded[1].Node())
+	assert.Equal(t, byte(0x80), decoded[0].Bytes()[8]&0xC0)
+}
+
+func TestUUIDv7TimeRange(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	gen := NewUUIDv7Generator(0, WithClock(clock))
+
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+
+	min, err := gen.Layout().MinIDForTime(testStartTime)
+	assert.NoError(t, err)
+	max, err := gen.Layout().MaxIDForTime(testStartTime)
+	assert.NoError(t, err)
+
+	assert.True(t, string(min) <= string(ids) && string(ids) <= string(max))
+}
@@ -0,0 +1,120 @@
+package flake
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForTimeNotYetAvailable(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	waitForTime := testStartTime.Add(10*time.Millisecond).UnixNano() / int64(time.Millisecond)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime, WithClock(clock))
+
+	_, err := gen.Generate(1)
+	assert.True(t, errors.Is(err, ErrNotYetAvailable))
+
+	var notYet *NotYetAvailableError
+	if assert.True(t, errors.As(err, &notYet)) {
+		assert.Equal(t, 10*time.Millisecond, notYet.Remaining)
+	}
+
+	// the clock did not move
+	assert.Equal(t, testStartTime, clock.Now())
+
+	clock.Advance(10 * time.Millisecond)
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(waitForTime), decodeAll(t, gen, ids)[0].Timestamp())
+}
+
+func TestWaitForTimeBlocks(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	waitForTime := testStartTime.Add(10*time.Millisecond).UnixNano() / int64(time.Millisecond)
+
+	// not willing to wait long enough
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime,
+		WithClock(clock), WithNotBeforeWait(5*time.Millisecond))
+	_, err := gen.Generate(1)
+	assert.True(t, errors.Is(err, ErrNotYetAvailable))
+	assert.Equal(t, testStartTime, clock.Now())
+
+	gen = NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime,
+		WithClock(clock), WithNotBeforeWait(20*time.Millisecond))
+	ids, err := gen.Generate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, testStartTime.Add(10*time.Millisecond), clock.Now())
+	assert.Equal(t, uint64(waitForTime), decodeAll(t, gen, ids)[0].Timestamp())
+}
+
+func TestWaitForTimeLockFree(t *testing.T) {
+	waitForTime := testStartTime.UnixNano() / int64(time.Millisecond)
+
+	// the mutex and lock-free paths both start with the waitForTime interval
+	for _, lockFree := range []bool{false, true} {
+		opts := []GeneratorOption{WithClock(NewFakeClock(testStartTime))}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, waitForTime, 2, opts...)
+		assert.Equal(t, waitForTime-1, gen.LastAllocatedTime())
+
+		ids, err := gen.Generate(4)
+		assert.NoError(t, err)
+
+		for index, id := range decodeAll(t, gen, ids) {
+			assert.Equal(t, uint64(waitForTime), id.Timestamp())
+			assert.Equal(t, uint64(index), id.Sequence())
+		}
+		assert.Equal(t, waitForTime, gen.LastAllocatedTime())
+	}
+}
+
+func TestReady(t *testing.T) {
+	// already ready
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, 0)
+	select {
+	case <-gen.Ready():
+	case <-time.After(time.Second):
+		assert.Fail(t, "Expecting generator to be ready")
+	}
+
+	waitFor := time.Now().Add(30 * time.Millisecond).Truncate(time.Millisecond)
+	gen = NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitFor.UnixNano()/int64(time.Millisecond))
+
+	_, err := gen.Generate(1)
+	assert.True(t, errors.Is(err, ErrNotYetAvailable))
+
+	select {
+	case <-gen.Ready():
+		assert.False(t, time.Now().Before(waitFor))
+	case <-time.After(time.Second):
+		assert.Fail(t, "Expecting generator to become ready")
+	}
+
+	_, err = gen.Generate(1)
+	assert.NoError(t, err)
+}
+
+func TestReadyFakeClock(t *testing.T) {
+	clock := NewFakeClock(testStartTime)
+	waitForTime := testStartTime.Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	gen := NewOvertFlakeGenerator(UnixEpochMs, testHardwareID, 42, waitForTime, WithClock(clock))
+
+	ready := gen.Ready()
+	select {
+	case <-ready:
+		assert.Fail(t, "Not expecting generator to be ready")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Hour)
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Expecting generator to become ready")
+	}
+}
@@ -0,0 +1,105 @@
+package flake
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// WaitStrategy determines how a generator waits for the next interval when
+// the sequence #'s for the current interval are exhausted. The generator lock
+// is never held while waiting
+type WaitStrategy int
+
+const (
+	// WaitHybrid sleeps until shortly before the next interval, and then
+	// yields until it arrives (the default)
+	WaitHybrid WaitStrategy = iota
+	// WaitSpin continuously checks the clock. It has the lowest latency, but
+	// burns a full core while waiting
+	WaitSpin
+	// WaitYield checks the clock, yielding the processor between checks
+	WaitYield
+	// WaitSleep sleeps until the next interval. It uses the least CPU, but
+	// typically oversleeps by the resolution of the OS timer
+	WaitSleep
+)
+
+// hybridSpinThreshold is how long before the next interval WaitHybrid stops
+// sleeping and starts yielding
+const hybridSpinThreshold = 100 * time.Microsecond
+
+// String returns the name of the strategy
+func (strategy WaitStrategy) String() string {
+	switch strategy {
+	case WaitHybrid:
+		return "hybrid"
+	case WaitSpin:
+		return "spin"
+	case WaitYield:
+		return "yield"
+	case WaitSleep:
+		return "sleep"
+	}
+
+	return "unknown"
+}
+
+// ParseWaitStrategy converts the name of a strategy (hybrid, spin, yield or
+// sleep) to a WaitStrategy
+func ParseWaitStrategy(name string) (WaitStrategy, error) {
+	switch strings.ToLower(name) {
+	case "hybrid":
+		return WaitHybrid, nil
+	case "spin":
+		return WaitSpin, nil
+	case "yield":
+		return WaitYield, nil
+	case "sleep":
+		return WaitSleep, nil
+	}
+
+	return WaitHybrid, fmt.Errorf("unknown wait strategy: %s", name)
+}
+
+// wait waits, according to the strategy, until d has elapsed on clock. It
+// returns ctx.Err() if ctx is done first.
+//
+// Spinning and yielding are bounded by the time remaining in real time, since
+// a Clock that is not driven by real time (such as FakeClock) only advances
+// when it is slept on. Any time that remains on clock is then slept for
+func (strategy WaitStrategy) wait(ctx context.Context, clock Clock, d time.Duration) error {
+	deadline := clock.Now().Add(d)
+	done := ctx.Done()
+
+	switch strategy {
+	case WaitSleep:
+		return sleepContext(ctx, clock, d)
+	case WaitHybrid:
+		if d > hybridSpinThreshold {
+			if err := sleepContext(ctx, clock, d-hybridSpinThreshold); err != nil {
+				return err
+			}
+		}
+	}
+
+	limit := time.Now().Add(deadline.Sub(clock.Now()))
+	for clock.Now().Before(deadline) && time.Now().Before(limit) {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+			if strategy != WaitSpin {
+				runtime.Gosched()
+			}
+		}
+	}
+
+	if remaining := deadline.Sub(clock.Now()); remaining > 0 {
+		return sleepContext(ctx, clock, remaining)
+	}
+
+	return nil
+}
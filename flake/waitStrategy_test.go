@@ -0,0 +1,83 @@
+package flake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testWaitStrategies = []WaitStrategy{WaitHybrid, WaitSpin, WaitYield, WaitSleep}
+
+func TestWaitStrategyWait(t *testing.T) {
+	clock := NewMonotonicClock()
+
+	for _, strategy := range testWaitStrategies {
+		start := clock.Now()
+		assert.NoError(t, strategy.wait(context.Background(), clock, 2*time.Millisecond))
+		assert.True(t, clock.Now().Sub(start) >= 2*time.Millisecond, "strategy %s", strategy)
+
+		// nothing to wait for
+		assert.NoError(t, strategy.wait(context.Background(), clock, -time.Millisecond))
+	}
+}
+
+func TestGeneratorWaitStrategy(t *testing.T) {
+	for _, strategy := range testWaitStrategies {
+		// 2 sequence bits == 4 ids per interval
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 2, WithWaitStrategy(strategy))
+
+		ids, err := gen.Generate(20)
+		assert.NoError(t, err)
+		assertIncreasing(t, gen.IDSize(), ids)
+
+		intervals := make(map[uint64]int)
+		for _, id := range decodeAll(t, gen, ids) {
+			intervals[id.Timestamp()]++
+		}
+
+		assert.True(t, len(intervals) >= 5, "strategy %s", strategy)
+		for _, count := range intervals {
+			assert.True(t, count <= 4, "strategy %s", strategy)
+		}
+	}
+}
+
+func TestGeneratorWaitStrategyFakeClock(t *testing.T) {
+	for _, strategy := range testWaitStrategies {
+		clock := NewFakeClock(testStartTime)
+		gen := NewOvertFlakeGeneratorWithBits(UnixEpochMs, testHardwareID, 42, 0, 2,
+			WithClock(clock), WithWaitStrategy(strategy))
+
+		// every strategy finishes by sleeping, which advances the fake clock to
+		// the next interval
+		ids, err := gen.Generate(6)
+		assert.NoError(t, err)
+		assert.Equal(t, testStartTime.Add(time.Millisecond), clock.Now(), "strategy %s", strategy)
+
+		decoded := decodeAll(t, gen, ids)
+		assert.True(t, testStartTime.Add(time.Millisecond).Equal(decoded[5].Time()), "strategy %s", strategy)
+		assert.Equal(t, uint64(1), decoded[5].Sequence(), "strategy %s", strategy)
+	}
+
+	// many exhausted intervals
+	clock := NewFakeClock(testStartTime)
+	gen := NewTwitterGenerator(1, 1, 0, WithClock(clock))
+
+	ids, err := gen.Generate(5000)
+	assert.NoError(t, err)
+	assertIncreasing(t, gen.IDSize(), ids)
+	assert.Equal(t, testStartTime.Add(time.Millisecond), clock.Now())
+}
+
+func TestParseWaitStrategy(t *testing.T) {
+	for _, strategy := range testWaitStrategies {
+		parsed, err := ParseWaitStrategy(strategy.String())
+		assert.NoError(t, err)
+		assert.Equal(t, strategy, parsed)
+	}
+
+	_, err := ParseWaitStrategy("nap")
+	assert.Error(t, err)
+}
@@ -3,11 +3,13 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"os/signal"
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gotomgo/overt-flake/flake"
 	"github.com/gotomgo/overt-flake/ofsserver"
@@ -23,12 +25,31 @@ Options:
     -auth            specify the sequence of characters that make up the auth token     default=""
     -config          specify a path to a configuration file                             default=""
     -hid             specify a hardware id to use when -hidype == "fixed"               default=""
-    -machineid       specify a machine id to use when -gentype == "twitter"             default=0
+    -machineid       specify a machine id to use when -gentype == "twitter"|"sonyflake" default=0
     -datacenterid    specify a data center id to use when -gentype == datacenterid      default=0
+    -snowflake       specify the preset used when -gentype == "snowflake"               default=twitter
+    -shardid         specify a shard id to use when -gentype == "snowflake"             default=0
+    -workerid        specify a worker id to use when -gentype == "snowflake"            default=0
+    -regression      specify the policy used when the clock moves backwards             default=fail
+    -regressionwait  specify the maximum wait for the clock to catch up (e.g. 500ms)    default=1s
+    -maxrequest      specify the maximum # of ids a client can request at once          default=1048576
+    -waitstrategy    specify how requests wait when an interval's ids are exhausted     default=hybrid
+    -lockfree        allocate ids with compare-and-swap rather than a mutex             default=false
+    -randomsequence  start the sequence #'s of each interval at a random offset         default=false
+    -lanes           specify the # of lanes (each with its own process id) for ids      default=1
+    -state           specify a path to a state file used to checkpoint the last time    default=""
+    -checkpoint      specify the interval between checkpoints of the state file         default=1s
+    -checkpointmargin specify the safety margin added to the checkpointed time           default=5s
+    -registry        specify a path to a registry file of nodes used when -gentype == "of64" default=""
+    -fleetsize       specify the # of servers used to report the node collision probability default=0
 
 Notes:
 * arguments specified on the command-line override values specified in -config file
 * waitfor *must* be specified on the command line
+* when -state is specified, the later of waitfor and the checkpointed time is used
+* checkpointmargin must be greater than checkpoint
+* requests received before waitfor is reached block until it is reached
+* when -registry is specified, the server does not start if its node is registered to another running server (hardware id + process id), and removes its node when it exits
 
 Hid Types:
     simple           simple MAC hardware ID provider
@@ -37,7 +58,30 @@ Hid Types:
 
 Generator Types:
     default          the standard overt-flake ID generator
+    of53             overt-flake ID generator with a 53-bit upper half (float64 safe)
+    of64             64-bit overt-flake ID generator (node is a hash of the hardware id and pid)
     twitter          Twitter snowflake ID generator
+    uuidv7           RFC 9562 UUIDv7 generator
+    ulid             ULID generator (monotonic within a millisecond)
+    sonyflake        Sonyflake ID generator (10ms intervals, 16-bit machine id)
+    layout           ids with the layout specified by the -config file (see README)
+    snowflake        64-bit Snowflake-family ID generator (see -snowflake)
+
+Snowflake Presets:
+    twitter          41-bit time, 5-bit shard (data center), 5-bit worker (machine), 12-bit seq #
+    instagram        41-bit time, 13-bit shard, 10-bit seq #
+    discord          42-bit time, 5-bit shard (worker), 5-bit worker (process), 12-bit seq #
+
+Clock Regression Policies:
+    fail             fail requests until the clock catches up (default)
+    wait             block requests until the clock catches up (up to -regressionwait)
+    reuse            use the sequence #'s remaining in the last interval, then wait
+
+Wait Strategies:
+    hybrid           sleep until just before the next interval, then yield (default)
+    spin             continuously check the clock (lowest latency, highest CPU)
+    yield            check the clock, yielding the processor between checks
+    sleep            sleep until the next interval (lowest CPU)
 
 Common Options:
     -help, --help    Show this message
@@ -98,18 +142,41 @@ func createOvertFlakeIDGenerator(
 	waitForTime int64,
 	machineid int64,
 	datacenterid int64,
+	lanes int,
+	opts ...flake.GeneratorOption,
 ) (flake.Generator, error) {
 	var generator flake.Generator
 
+	switch strings.ToLower(genType) {
+	case "default", "of53":
+	default:
+		if lanes > 1 {
+			return nil, fmt.Errorf("lanes are not supported for generator type: %s", genType)
+		}
+	}
+
 	switch strings.ToLower(genType) {
 	case "default":
-		generator = flake.NewOvertFlakeGenerator(epoch, hardwareID, os.Getpid(), waitForTime)
-		break
+		return flake.NewOvertFlakeLaneGenerator(epoch, hardwareID, lanes, waitForTime, opts...)
 	case "of53":
-		generator = flake.NewOvertFlakeGenerator53(hardwareID, os.Getpid(), waitForTime)
-		break
+		return flake.NewOvertFlakeLaneGenerator53(hardwareID, lanes, waitForTime, opts...)
 	case "twitter":
-		generator = flake.NewTwitterGenerator(machineid, datacenterid, waitForTime)
+		return flake.NewSnowflakeGenerator(flake.TwitterSnowflake, datacenterid, machineid, waitForTime, opts...)
+	case "uuidv7":
+		generator = flake.NewUUIDv7Generator(waitForTime, opts...)
+		break
+	case "ulid":
+		generator = flake.NewULIDGenerator(waitForTime, opts...)
+		break
+	case "of64":
+		generator = flake.NewOvertFlakeGenerator64(hardwareID, os.Getpid(), waitForTime, opts...)
+		break
+	case "sonyflake":
+		if machineid < 0 || machineid > math.MaxUint16 {
+			return nil, fmt.Errorf("the machine id of a sonyflake must be in the range 0-%d: %d", math.MaxUint16, machineid)
+		}
+
+		generator = flake.NewSonyflakeGenerator(uint16(machineid), waitForTime, opts...)
 		break
 	default:
 		showErrorWithUsage("Unsupported type for Generator: %s", genType)
@@ -118,6 +185,114 @@ func createOvertFlakeIDGenerator(
 	return generator, nil
 }
 
+// createLayoutGenerator creates a generator for the layout specified by a
+// configuration file
+func createLayoutGenerator(
+	layoutConfig *layoutConfig,
+	epoch int64,
+	hardwareID flake.HardwareID,
+	waitForTime int64,
+	lanes int,
+	opts ...flake.GeneratorOption,
+) (flake.Generator, error) {
+	if layoutConfig == nil {
+		return nil, fmt.Errorf("generator type layout requires a layout in the configuration file")
+	}
+
+	if lanes > 1 {
+		return nil, fmt.Errorf("lanes are not supported for generator type: layout")
+	}
+
+	if layoutConfig.Bits%8 != 0 {
+		return nil, fmt.Errorf("the bits of a layout must be 64, 96 or 128, not %d", layoutConfig.Bits)
+	}
+
+	if layoutConfig.Epoch != nil {
+		epoch = *layoutConfig.Epoch
+	}
+
+	order := flake.TimeSequenceNode
+	if len(layoutConfig.Order) > 0 {
+		var err error
+		if order, err = flake.ParseFieldOrder(layoutConfig.Order); err != nil {
+			return nil, err
+		}
+	}
+
+	var source flake.NodeSource
+	switch strings.ToLower(layoutConfig.Node) {
+	case "fixed":
+		source = flake.NewFixedNodeSource(layoutConfig.NodeValue)
+	case "pid":
+		source = flake.NewProcessNodeSource(os.Getpid())
+	case "hid":
+		source = flake.NewHardwareNodeSource(hardwareID, os.Getpid())
+	default:
+		return nil, fmt.Errorf("unsupported node source for layout: %q (fixed,pid,hid)", layoutConfig.Node)
+	}
+
+	layout := flake.Layout{
+		IDSize:       layoutConfig.Bits / 8,
+		Epoch:        epoch,
+		TimeBits:     layoutConfig.TimeBits,
+		SequenceBits: layoutConfig.SequenceBits,
+		NodeBits:     layoutConfig.NodeBits,
+		Order:        order,
+		TimeUnit:     layoutConfig.TimeUnit,
+	}
+
+	return flake.NewLayoutGenerator(layout, source, waitForTime, opts...)
+}
+
+// createSnowflakeGenerator creates a generator for a Snowflake-family preset
+func createSnowflakeGenerator(
+	preset string,
+	shardID int64,
+	workerID int64,
+	waitForTime int64,
+	lanes int,
+	opts ...flake.GeneratorOption,
+) (flake.Generator, error) {
+	if lanes > 1 {
+		return nil, fmt.Errorf("lanes are not supported for generator type: snowflake")
+	}
+
+	if len(preset) == 0 {
+		preset = "twitter"
+	}
+
+	config, err := flake.ParseSnowflakePreset(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	return flake.NewSnowflakeGenerator(config, shardID, workerID, waitForTime, opts...)
+}
+
+// clockRegressionOptions creates the generator options that apply a clock
+// regression policy and report regressions
+func clockRegressionOptions(policyName string, maxWait time.Duration) ([]flake.GeneratorOption, error) {
+	if len(policyName) == 0 {
+		policyName = flake.ClockRegressionFail.String()
+	}
+
+	policy, err := flake.ParseClockRegressionPolicy(policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxWait <= 0 {
+		maxWait = flake.DefaultClockRegressionMaxWait
+	}
+
+	return []flake.GeneratorOption{
+		flake.WithClockRegressionPolicy(policy, maxWait),
+		flake.WithClockRegressionHandler(func(regression flake.ClockRegression) {
+			fmt.Fprintf(os.Stderr, "Clock moved backwards by %s (policy = %s)\n", regression.Drift(), regression.Policy)
+		}),
+	}, nil
+}
+
 func main() {
 	showAppVersion()
 
@@ -130,6 +305,21 @@ func main() {
 	var argHardwareID string
 	var argMachineID int64
 	var argDataCenterID int64
+	var argSnowflake string
+	var argShardID int64
+	var argWorkerID int64
+	var argClockRegression string
+	var argClockRegressionMaxWait time.Duration
+	var argMaxRequestSize int
+	var argWaitStrategy string
+	var argLockFree bool
+	var argRandomSequence bool
+	var argLanes int
+	var argStateFile string
+	var argCheckpointInterval time.Duration
+	var argCheckpointMargin time.Duration
+	var argNodeRegistry string
+	var argFleetSize int
 
 	// other args
 	var waitForTime int64
@@ -139,15 +329,30 @@ func main() {
 	flag.StringVar(&argIPAddr, "ip", "", "the interface/address to listen on")
 	flag.Int64Var(&waitForTime, "waitfor", 0, "the time to wait for prior to generating ids")
 	flag.StringVar(&argHidType, "hidtype", "", "the hardware id provider")
-	flag.StringVar(&argGenType, "gentype", "", "the type of the id generator (default,of53,twitter)")
+	flag.StringVar(&argGenType, "gentype", "", "the type of the id generator (default,of53,of64,twitter,uuidv7,ulid,sonyflake,layout,snowflake)")
 	flag.StringVar(&argAuthToken, "auth", "", "the auth token used to authenticate clients")
 	flag.Int64Var(&argEpoch, "epoch", -1, "the epoch used for id generation")
 	flag.BoolVar(&showVersion, "version", false, "print ofsrvr version information")
 	flag.BoolVar(&showVersion, "v", false, "print ofsrvr version information")
 	flag.StringVar(&configPath, "config", "", "the path to a ofs server configuration file")
 	flag.StringVar(&argHardwareID, "hid", "", "the fixed hardware id")
-	flag.Int64Var(&argMachineID, "machineid", 0, "the machineid used for twitter snowflake id's")
+	flag.Int64Var(&argMachineID, "machineid", 0, "the machineid used for twitter snowflake and sonyflake id's")
 	flag.Int64Var(&argDataCenterID, "datacenterid", 0, "the datacenterid used for twitter snowflake id's")
+	flag.StringVar(&argSnowflake, "snowflake", "", "the preset used for snowflake id's (twitter,instagram,discord)")
+	flag.Int64Var(&argShardID, "shardid", 0, "the shard id used for snowflake id's")
+	flag.Int64Var(&argWorkerID, "workerid", 0, "the worker id used for snowflake id's")
+	flag.StringVar(&argClockRegression, "regression", "", "the policy used when the clock moves backwards (fail,wait,reuse)")
+	flag.DurationVar(&argClockRegressionMaxWait, "regressionwait", 0, "the maximum wait for the clock to catch up")
+	flag.IntVar(&argMaxRequestSize, "maxrequest", 0, "the maximum # of ids a client can request at once")
+	flag.StringVar(&argWaitStrategy, "waitstrategy", "", "how requests wait for the next interval (hybrid,spin,yield,sleep)")
+	flag.BoolVar(&argLockFree, "lockfree", false, "allocate ids with compare-and-swap rather than a mutex")
+	flag.BoolVar(&argRandomSequence, "randomsequence", false, "start the sequence #'s of each interval at a random offset")
+	flag.IntVar(&argLanes, "lanes", 0, "the # of lanes used to generate ids (default,of53)")
+	flag.StringVar(&argStateFile, "state", "", "the path to a state file used to checkpoint the last allocated time")
+	flag.DurationVar(&argCheckpointInterval, "checkpoint", 0, "the interval between checkpoints")
+	flag.DurationVar(&argCheckpointMargin, "checkpointmargin", 0, "the safety margin added to the checkpointed time")
+	flag.StringVar(&argNodeRegistry, "registry", "", "the path to a registry file of the nodes in use (of64)")
+	flag.IntVar(&argFleetSize, "fleetsize", 0, "the # of servers used to report the node collision probability (of64)")
 
 	flag.Usage = showUsage
 	flag.Parse()
@@ -221,6 +426,79 @@ func main() {
 		config.DataCenterID = argDataCenterID
 	}
 
+	if len(argSnowflake) > 0 {
+		config.Snowflake = argSnowflake
+	}
+
+	if argShardID > 0 {
+		config.ShardID = argShardID
+	}
+
+	if argWorkerID > 0 {
+		config.WorkerID = argWorkerID
+	}
+
+	if len(argClockRegression) > 0 {
+		config.ClockRegression = argClockRegression
+	}
+
+	if argClockRegressionMaxWait > 0 {
+		config.ClockRegressionMaxWait = argClockRegressionMaxWait
+	}
+
+	if argMaxRequestSize > 0 {
+		config.MaxRequestSize = argMaxRequestSize
+	}
+
+	if len(argWaitStrategy) > 0 {
+		config.WaitStrategy = argWaitStrategy
+	}
+
+	if argLockFree {
+		config.LockFree = true
+	}
+
+	if argRandomSequence {
+		config.RandomSequenceStart = true
+	}
+
+	if argLanes > 0 {
+		config.Lanes = argLanes
+	}
+
+	if len(argStateFile) > 0 {
+		config.StateFile = argStateFile
+	}
+
+	if argCheckpointInterval > 0 {
+		config.CheckpointInterval = argCheckpointInterval
+	}
+
+	if argCheckpointMargin > 0 {
+		config.CheckpointMargin = argCheckpointMargin
+	}
+
+	if len(argNodeRegistry) > 0 {
+		config.NodeRegistry = argNodeRegistry
+	}
+
+	if argFleetSize > 0 {
+		config.FleetSize = argFleetSize
+	}
+
+	if config.CheckpointInterval <= 0 {
+		config.CheckpointInterval = flake.DefaultCheckpointInterval
+	}
+
+	if config.CheckpointMargin <= 0 {
+		config.CheckpointMargin = flake.DefaultCheckpointMargin
+	}
+
+	if config.CheckpointMargin <= config.CheckpointInterval {
+		showErrorWithUsage("The checkpoint margin (%s) must be greater than the checkpoint interval (%s)",
+			config.CheckpointMargin, config.CheckpointInterval)
+	}
+
 	//	---------------------------------------------------------
 	//	Create the components needed to run the server
 	//
@@ -242,12 +520,92 @@ func main() {
 		showError("Error generating Hardware ID: %s", err)
 	}
 
+	// determine how the generator handles the clock moving backwards
+	opts, err := clockRegressionOptions(config.ClockRegression, config.ClockRegressionMaxWait)
+	if err != nil {
+		showErrorWithUsage("%s", err)
+	}
+
+	if config.MaxRequestSize > 0 {
+		opts = append(opts, flake.WithMaxRequestSize(config.MaxRequestSize))
+	}
+
+	if len(config.WaitStrategy) > 0 {
+		waitStrategy, err := flake.ParseWaitStrategy(config.WaitStrategy)
+		if err != nil {
+			showErrorWithUsage("%s", err)
+		}
+		opts = append(opts, flake.WithWaitStrategy(waitStrategy))
+	}
+
+	if config.LockFree {
+		opts = append(opts, flake.WithLockFree())
+	}
+
+	if config.RandomSequenceStart {
+		opts = append(opts, flake.WithRandomSequenceStart())
+	}
+
+	// ids are never generated for an interval before the checkpointed time
+	if len(config.StateFile) > 0 {
+		highWater, err := flake.ReadCheckpoint(config.StateFile)
+		if err != nil {
+			showError("Error reading checkpoint from '%s': %s", config.StateFile, err)
+		}
+
+		if highWater > waitForTime {
+			waitForTime = highWater
+		}
+	}
+
 	// create an ID generator
-	generator, err := createOvertFlakeIDGenerator(config.GenType, config.Epoch, hid, waitForTime, config.MachineID, config.DataCenterID)
+	var generator flake.Generator
+	switch strings.ToLower(config.GenType) {
+	case "layout":
+		generator, err = createLayoutGenerator(config.Layout, config.Epoch, hid, waitForTime, config.Lanes, opts...)
+	case "snowflake":
+		generator, err = createSnowflakeGenerator(config.Snowflake, config.ShardID, config.WorkerID, waitForTime, config.Lanes, opts...)
+	default:
+		generator, err = createOvertFlakeIDGenerator(config.GenType, config.Epoch, hid, waitForTime, config.MachineID, config.DataCenterID, config.Lanes, opts...)
+	}
 	if err != nil {
 		showError("Error creating Overt-Flake generator: %s", err)
 	}
 
+	// the generator falls back to a mutex when the state cannot be packed
+	if config.LockFree && !flake.SupportsLockFree(generator.IDGenerator()) {
+		fmt.Fprintf(os.Stderr, "Lock-free allocation requires the time and sequence bits of the %s generator to fit in 64 bits, using a mutex\n", config.GenType)
+		config.LockFree = false
+	}
+
+	// a hashed node may collide with the node of another server, which is
+	// detected by registering the node before any ids are generated
+	var registered flake.HashedNodeIDGenerator
+	hashed, isHashed := generator.IDGenerator().(flake.HashedNodeIDGenerator)
+	if isHashed && len(config.NodeRegistry) > 0 {
+		err = flake.RegisterNode(config.NodeRegistry, hashed.Node(), nodeOwner(hashed))
+		if err != nil {
+			showError("Error registering node %d in '%s': %s", hashed.Node(), config.NodeRegistry, err)
+		}
+		registered = hashed
+	}
+
+	// checkpoint the generator so that a restart never reuses an interval. A
+	// checkpoint is saved before any ids are generated
+	var checkpointer flake.Checkpointer
+	if len(config.StateFile) > 0 {
+		checkpointer = flake.NewCheckpointer(generator, config.StateFile, config.CheckpointMargin)
+
+		err = checkpointer.Checkpoint()
+		if err != nil {
+			showError("Error saving checkpoint to '%s': %s", config.StateFile, err)
+		}
+
+		checkpointer.Start(config.CheckpointInterval, func(err error) {
+			fmt.Fprintf(os.Stderr, "Error saving checkpoint to '%s': %s\n", config.StateFile, err)
+		})
+	}
+
 	// create an OvertFlakeServer
 	server, err := ofsserver.NewOvertFlakeServer(generator, config.IPAddr, config.AuthToken)
 	if err != nil {
@@ -255,11 +613,13 @@ func main() {
 	}
 
 	// Wait for SIGINT and SIGTERM (HIT CTRL-C)
-	ch := make(chan os.Signal)
+	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		for _ = range ch {
+		for range ch {
 			fmt.Fprintln(os.Stderr, "\nExiting ofsrvr...")
+			stopCheckpointer(checkpointer)
+			unregisterNode(config.NodeRegistry, registered)
 			os.Exit(0)
 		}
 	}()
@@ -269,12 +629,50 @@ func main() {
 	//	---------------------------------------------------------
 
 	fmt.Fprintf(os.Stderr, "Starting overt-flake ID server on %s\n", config.IPAddr)
-	fmt.Fprintf(os.Stderr, "  with epoch = %d\n", config.Epoch)
+	fmt.Fprintf(os.Stderr, "  with epoch = %d\n", generator.Epoch())
 	fmt.Fprintf(os.Stderr, "  with hardware id = %v\n", hid)
 	fmt.Fprintf(os.Stderr, "  with generator type = %s\n", config.GenType)
 
+	if isHashed {
+		fmt.Fprintf(os.Stderr, "  with node = %d\n", hashed.Node())
+
+		if config.FleetSize > 1 {
+			fmt.Fprintf(os.Stderr, "  with node collision probability = %.4g%% (fleet of %d)\n",
+				100*hashed.CollisionProbability(config.FleetSize), config.FleetSize)
+		}
+	}
+
+	if len(config.ClockRegression) > 0 {
+		fmt.Fprintf(os.Stderr, "  with clock regression policy = %s\n", config.ClockRegression)
+	}
+
+	if config.MaxRequestSize > 0 {
+		fmt.Fprintf(os.Stderr, "  with max request size = %d\n", config.MaxRequestSize)
+	}
+
+	if len(config.WaitStrategy) > 0 {
+		fmt.Fprintf(os.Stderr, "  with wait strategy = %s\n", config.WaitStrategy)
+	}
+
+	if config.LockFree {
+		fmt.Fprintln(os.Stderr, "  with lock-free allocation")
+	}
+
+	if config.RandomSequenceStart {
+		fmt.Fprintln(os.Stderr, "  with random sequence start")
+	}
+
+	if config.Lanes > 1 {
+		fmt.Fprintf(os.Stderr, "  with lanes = %d\n", config.Lanes)
+	}
+
 	if waitForTime != 0 {
-		fmt.Fprintf(os.Stderr, "  with waitForTime = %d\n", waitForTime)
+		fmt.Fprintf(os.Stderr, "  with waitForTime = %d (requests block until it is reached)\n", waitForTime)
+	}
+
+	if checkpointer != nil {
+		fmt.Fprintf(os.Stderr, "  with state file = %s (every %s, margin %s)\n",
+			config.StateFile, config.CheckpointInterval, config.CheckpointMargin)
 	}
 
 	if len(config.AuthToken) == 0 {
@@ -283,6 +681,16 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  with server AUTH enabled (%s)\n", config.AuthToken)
 	}
 
+	// let the operator know when ids become available
+	select {
+	case <-generator.Ready():
+	default:
+		go func() {
+			<-generator.Ready()
+			fmt.Fprintln(os.Stderr, "waitForTime reached, ids are now available")
+		}()
+	}
+
 	//	---------------------------------------------------------
 	//	Run the server
 	//	---------------------------------------------------------
@@ -291,4 +699,35 @@ func main() {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Exiting ofsrvr: %s", err)
 	}
+
+	stopCheckpointer(checkpointer)
+	unregisterNode(config.NodeRegistry, registered)
+}
+
+// nodeOwner is the owner of the node of hashed in a node registry
+func nodeOwner(hashed flake.HashedNodeIDGenerator) string {
+	return flake.NodeOwner(hashed.HardwareID(), hashed.ProcessID())
+}
+
+// unregisterNode removes the node of registered (if any) from the node
+// registry
+func unregisterNode(registryPath string, registered flake.HashedNodeIDGenerator) {
+	if registered == nil {
+		return
+	}
+
+	if err := flake.UnregisterNode(registryPath, registered.Node(), nodeOwner(registered)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error unregistering node %d in '%s': %s\n", registered.Node(), registryPath, err)
+	}
+}
+
+// stopCheckpointer stops checkpointer (if any), saving a final checkpoint
+func stopCheckpointer(checkpointer flake.Checkpointer) {
+	if checkpointer == nil {
+		return
+	}
+
+	if err := checkpointer.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving checkpoint: %s\n", err)
+	}
 }
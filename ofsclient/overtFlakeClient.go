@@ -137,3 +137,33 @@ func (c *overtFlakeClient) GenerateFlake() (flake.OvertFlakeID, error) {
 	}
 	return ids[0], nil
 }
+
+// StreamIDs generates ids in chunks passing them back to the caller as they arrive, via ids
+func (c *overtFlakeClient) StreamIDs(count int, ids []flake.ID,
+	callback func(int, []flake.ID) error) (totalAllocated int, err error) {
+
+	// create a byte buffer with a length that corresponds to the ids buffer provided by caller
+	buffer := make([]byte, len(ids)*flake.OvertFlakeIDLength)
+	totalAllocated, err = c.StreamIDBytes(count, buffer, func(count int, idBytes []byte) error {
+		// as the id bytes stream in, copy them into the caller's ids
+		_, err := flake.AppendIDs(ids[:0], idBytes[0:count*flake.OvertFlakeIDLength])
+		if err != nil {
+			return err
+		}
+
+		// send the ids to our caller
+		return callback(count, ids)
+	})
+
+	return
+}
+
+// GenerateIDs generates count overt-flake identifiers in the form of []flake.ID
+func (c *overtFlakeClient) GenerateIDs(count int) (ids []flake.ID, err error) {
+	idBytes, err := c.GenerateIDBytes(count)
+	if err != nil {
+		return
+	}
+
+	return flake.IDsFromBytes(idBytes)
+}
@@ -37,7 +37,8 @@ type OvertFlakeClient interface {
 	// GenerateBigInts generates count IDs in the form of []big.Int
 	GenerateBigInts(count int) (bigInts []big.Int, err error)
 
-	// StreamFlakes generates ids in chunks passing them back to the caller as they arrive, via buffer
+	// StreamFlakes generates ids in chunks passing them back to the caller as they arrive, via buffer.
+	// The flakes passed to callback alias an internal buffer that is overwritten by the next chunk
 	StreamFlakes(count int, flakes []flake.OvertFlakeID, callback func(int, []flake.OvertFlakeID) error) (totalAllocated int, err error)
 
 	// GenerateFlakes generates count IDs in the form of []flake.OvertFlakeID
@@ -45,4 +46,11 @@ type OvertFlakeClient interface {
 
 	// GenerateFlake generates a single ID in the form of *flake.OvertFlakeID
 	GenerateFlake() (flake flake.OvertFlakeID, err error)
+
+	// StreamIDs generates ids in chunks passing them back to the caller as they arrive, via ids.
+	// Because flake.ID is a value type, ids copied out of the callback are safe to keep
+	StreamIDs(count int, ids []flake.ID, callback func(int, []flake.ID) error) (totalAllocated int, err error)
+
+	// GenerateIDs generates count IDs in the form of []flake.ID
+	GenerateIDs(count int) (ids []flake.ID, err error)
 }
@@ -129,6 +129,10 @@ func (server *OvertFlakeServer) serveClient(reader io.Reader, writer io.Writer)
 			return ErrInvalidReauthentication
 		}
 
+		// requests received before the generator is ready are held (rather than
+		// failed) until ids are available
+		<-server.generator.Ready()
+
 		_, err = server.generator.GenerateAsStream(int(count), buffer, func(allocated int, ids []byte) error {
 			var bytesWritten int
 
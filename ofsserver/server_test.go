@@ -0,0 +1,81 @@
+package ofsserver
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gotomgo/overt-flake/flake"
+	"github.com/stretchr/testify/assert"
+)
+
+var testHardwareID = flake.HardwareID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+// request sends a request for count ids to conn, and reads the response
+func request(conn net.Conn, count int, idSize int) ([]byte, error) {
+	countBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(countBytes, uint32(count))
+
+	if _, err := conn.Write(countBytes); err != nil {
+		return nil, err
+	}
+
+	ids := make([]byte, count*idSize)
+	_, err := io.ReadFull(conn, ids)
+
+	return ids, err
+}
+
+func TestServeClient(t *testing.T) {
+	generator := flake.NewOvertFlakeGenerator(flake.OvertoneEpochMs, testHardwareID, 42, 0)
+	server, err := NewOvertFlakeServer(generator, "localhost:0", "")
+	assert.NoError(t, err)
+
+	client, conn := net.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		defer conn.Close()
+		served <- server.serveClient(conn, conn)
+	}()
+
+	ids, err := request(client, 20, generator.IDSize())
+	assert.NoError(t, err)
+	assert.Len(t, ids, 20*generator.IDSize())
+
+	client.Close()
+	assert.Equal(t, io.EOF, <-served)
+}
+
+func TestServeClientBeforeWaitForTime(t *testing.T) {
+	// requests do not wait for waitForTime in the generator, so only the
+	// server holds them
+	waitFor := time.Now().Add(100 * time.Millisecond).Truncate(time.Millisecond)
+	generator := flake.NewOvertFlakeGenerator(flake.OvertoneEpochMs, testHardwareID, 42,
+		waitFor.UnixNano()/int64(time.Millisecond))
+	server, err := NewOvertFlakeServer(generator, "localhost:0", "")
+	assert.NoError(t, err)
+
+	client, conn := net.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		defer conn.Close()
+		served <- server.serveClient(conn, conn)
+	}()
+
+	// the request blocks until ids are available rather than the connection
+	// being closed
+	ids, err := request(client, 3, generator.IDSize())
+	assert.NoError(t, err)
+	assert.False(t, time.Now().Before(waitFor))
+
+	for i := 0; i < 3; i++ {
+		decoded, err := generator.Decode(ids[i*generator.IDSize() : (i+1)*generator.IDSize()])
+		assert.NoError(t, err)
+		assert.False(t, decoded.Time().Before(waitFor))
+	}
+
+	client.Close()
+	assert.Equal(t, io.EOF, <-served)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"io/ioutil"
+	"time"
 
 	yaml "gopkg.in/yaml.v2"
 )
@@ -17,6 +18,49 @@ type serverConfig struct {
 	HardwareID   []byte `yaml:"hardwareId"`
 	MachineID    int64  `yaml:"machineId"`
 	DataCenterID int64  `yaml:"dataCenterId"`
+	Snowflake    string `yaml:"snowflake"`
+	ShardID      int64  `yaml:"shardId"`
+	WorkerID     int64  `yaml:"workerId"`
+
+	ClockRegression        string        `yaml:"clockRegression"`
+	ClockRegressionMaxWait time.Duration `yaml:"clockRegressionMaxWait"`
+	MaxRequestSize         int           `yaml:"maxRequestSize"`
+	WaitStrategy           string        `yaml:"waitStrategy"`
+	LockFree               bool          `yaml:"lockFree"`
+	RandomSequenceStart    bool          `yaml:"randomSequenceStart"`
+	Lanes                  int           `yaml:"lanes"`
+
+	StateFile          string        `yaml:"stateFile"`
+	CheckpointInterval time.Duration `yaml:"checkpointInterval"`
+	CheckpointMargin   time.Duration `yaml:"checkpointMargin"`
+
+	NodeRegistry string `yaml:"nodeRegistry"`
+	FleetSize    int    `yaml:"fleetSize"`
+
+	Layout *layoutConfig `yaml:"layout,omitempty"`
+}
+
+// layoutConfig is a yaml representation of the layout of the ids generated
+// when genType is "layout"
+//
+//	bits is the size of an id: 64, 96 or 128
+//	epoch is the epoch of the time field (ms since the Unix Epoch). The
+//		server epoch is used if it is not specified
+//	timeBits, sequenceBits and nodeBits are the widths of the fields
+//	order is the order of the fields (timeSequenceNode or timeNodeSequence)
+//	timeUnit is the duration of an interval (1us, 1ms or 10ms, etc)
+//	node is the source of the node field: fixed (nodeValue), pid (the process
+//		id) or hid (the hardware id followed by the process id)
+type layoutConfig struct {
+	Bits         int           `yaml:"bits"`
+	Epoch        *int64        `yaml:"epoch,omitempty"`
+	TimeBits     uint64        `yaml:"timeBits"`
+	SequenceBits uint64        `yaml:"sequenceBits"`
+	NodeBits     uint64        `yaml:"nodeBits"`
+	Order        string        `yaml:"order"`
+	TimeUnit     time.Duration `yaml:"timeUnit"`
+	Node         string        `yaml:"node"`
+	NodeValue    uint64        `yaml:"nodeValue"`
 }
 
 // loadConfig loads bytes from a file and calls a function to